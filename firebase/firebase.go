@@ -4,16 +4,22 @@ import (
 	"context"
 	"log"
 	"os"
+	"cloud.google.com/go/storage"
 	"firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/db"
 	"google.golang.org/api/option"
 )
 
 var App *firebase.App
+var DB *db.Client
+// Bucket is nil unless FIREBASE_STORAGE_BUCKET is set
+var Bucket *storage.BucketHandle
 
 func init() {
 	ctx := context.Background()
 	conf := &firebase.Config{
 		DatabaseURL: os.Getenv("FIREBASE_DB_URL"),
+		StorageBucket: os.Getenv("FIREBASE_STORAGE_BUCKET"),
 	}
 	opt := option.WithCredentialsFile("serviceAccountKey.json")
 	var err error
@@ -21,4 +27,18 @@ func init() {
 	if err != nil {
 		log.Fatalln("Error initializing app", err)
 	}
+	DB, err = App.Database(ctx)
+	if err != nil {
+		log.Fatalln("Error initializing database client", err)
+	}
+	if conf.StorageBucket != "" {
+		client, err := App.Storage(ctx)
+		if err != nil {
+			log.Fatalln("Error initializing storage client", err)
+		}
+		Bucket, err = client.DefaultBucket()
+		if err != nil {
+			log.Fatalln("Error getting storage bucket", err)
+		}
+	}
 }
\ No newline at end of file
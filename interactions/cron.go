@@ -0,0 +1,246 @@
+package interactions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurring jobs repeat on a cron expression with five fields (minute, hour,
+// day of month, month, day of week) or six with seconds first. Fields take
+// "*", numbers, ranges like "1-5", steps like "*/15" and lists of those, and
+// months and days of week can be given by name. As in cron, a day matches if
+// either of the day fields matches when both are restricted.
+//
+// Occurrences are computed on the wall clock of the recurrence's timezone, so
+// "daily 09:00" stays at 9am across DST changes. A time skipped by a DST jump
+// fires right after the jump, and a time repeated by one fires once.
+
+// Occurrences are searched this far ahead before a schedule is considered
+// to never fire
+const CRON_SEARCH_DAYS = 5 * 366
+
+var CRON_MONTH_NAMES = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var CRON_WEEKDAY_NAMES = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Recurrence is how a recurring job repeats
+type Recurrence struct {
+	Cron string `json:"cron"`
+	Timezone string `json:"timezone"`
+	// Last time the job may fire in Unix epoch milliseconds, 0 if unbounded
+	Until int64 `json:"until,omitempty"`
+	// Number of times the job may fire, 0 if unbounded
+	MaxOccurrences int `json:"maxOccurrences,omitempty"`
+	Occurrences int `json:"occurrences,omitempty"`
+}
+
+type cronSchedule struct {
+	seconds, minutes, hours, days, months, weekdays uint64
+	anyDay, anyWeekday bool
+}
+
+// parseCron parses a cron expression
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 5 {
+		fields = append([]string{"0"}, fields...)
+	}
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("cron expression %q must have 5 or 6 fields", expr)
+	}
+	schedule := &cronSchedule{
+		anyDay: fields[3] == "*" || fields[3] == "?",
+		anyWeekday: fields[5] == "*" || fields[5] == "?",
+	}
+	var err error
+	if schedule.seconds, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if schedule.minutes, err = parseCronField(fields[1], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if schedule.hours, err = parseCronField(fields[2], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if schedule.days, err = parseCronField(fields[3], 1, 31, nil); err != nil {
+		return nil, err
+	}
+	if schedule.months, err = parseCronField(fields[4], 1, 12, CRON_MONTH_NAMES); err != nil {
+		return nil, err
+	}
+	// Sunday is both 0 and 7
+	if schedule.weekdays, err = parseCronField(fields[5], 0, 7, CRON_WEEKDAY_NAMES); err != nil {
+		return nil, err
+	}
+	if schedule.weekdays & (1 << 7) != 0 {
+		schedule.weekdays |= 1
+	}
+	return schedule, nil
+}
+
+// parseCronField returns the values a field matches as a bitset
+func parseCronField(field string, minValue, maxValue int, names map[string]int) (uint64, error) {
+	parseValue := func(value string) (int, error) {
+		if n, ok := names[strings.ToLower(value)]; ok {
+			return n, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < minValue || n > maxValue {
+			return 0, fmt.Errorf("invalid cron value %q", value)
+		}
+		return n, nil
+	}
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid cron step %q", stepPart)
+			}
+		}
+		start, end := minValue, maxValue
+		if rangePart != "*" && rangePart != "?" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseValue(startPart); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = parseValue(endPart); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = maxValue
+			}
+			if end < start {
+				return 0, fmt.Errorf("invalid cron range %q", rangePart)
+			}
+		}
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	day := c.days & (1 << t.Day()) != 0
+	weekday := c.weekdays & (1 << int(t.Weekday())) != 0
+	if c.anyDay || c.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// next returns the first occurrence after a time, or the zero time if there
+// is none within CRON_SEARCH_DAYS
+func (c *cronSchedule) next(after time.Time, loc *time.Location) time.Time {
+	local := after.In(loc)
+	for dayOffset := 0; dayOffset < CRON_SEARCH_DAYS; dayOffset++ {
+		date := time.Date(local.Year(), local.Month(), local.Day() + dayOffset, 12, 0, 0, 0, loc)
+		if c.months & (1 << int(date.Month())) == 0 || !c.matchesDay(date) {
+			continue
+		}
+		for hour := 0; hour < 24; hour++ {
+			if c.hours & (1 << hour) == 0 {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if c.minutes & (1 << minute) == 0 {
+					continue
+				}
+				for second := 0; second < 60; second++ {
+					if c.seconds & (1 << second) == 0 {
+						continue
+					}
+					t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, 0, loc)
+					if t.Hour() != hour || t.Minute() != minute {
+						// The time is in a DST gap, which Go resolves with the
+						// offset from before the jump
+						_, before := t.Zone()
+						_, jumped := t.Add(24 * time.Hour).Zone()
+						t = t.Add(time.Duration(jumped - before) * time.Second)
+					}
+					if t.After(after) {
+						return t
+					}
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// parseRepeat turns a repeat option into a cron expression. Besides cron
+// expressions it takes "daily <time>" and "weekly <days> <time>", with days
+// separated by commas.
+func parseRepeat(value string) (string, error) {
+	invalid := localizedError{"send.invalidRepeat", []any{value}}
+	fields := strings.Fields(strings.ToLower(value))
+	if len(fields) == 0 {
+		return "", invalid
+	}
+	var days string
+	switch fields[0] {
+	case "daily":
+		if len(fields) != 2 {
+			return "", invalid
+		}
+		days = "*"
+	case "weekly":
+		if len(fields) != 3 {
+			return "", invalid
+		}
+		var weekdays []string
+		for _, day := range strings.Split(fields[1], ",") {
+			weekday, ok := WEEKDAY_NAMES[day]
+			if !ok {
+				return "", invalid
+			}
+			weekdays = append(weekdays, strconv.Itoa(int(weekday)))
+		}
+		days = strings.Join(weekdays, ",")
+	default:
+		if _, err := parseCron(value); err != nil {
+			return "", invalid
+		}
+		return value, nil
+	}
+	hour, minute, second, _, ok := parseTimeOfDay(fields[len(fields) - 1], true)
+	if !ok {
+		return "", invalid
+	}
+	return fmt.Sprintf("%d %d %d * * %s", second, minute, hour, days), nil
+}
+
+// next returns when a recurring job fires after a time, and false if it has
+// reached its end
+func (r *Recurrence) next(after time.Time) (time.Time, bool) {
+	if r.MaxOccurrences > 0 && r.Occurrences >= r.MaxOccurrences {
+		return time.Time{}, false
+	}
+	schedule, err := parseCron(r.Cron)
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	t := schedule.next(after, loc)
+	if t.IsZero() || (r.Until > 0 && t.UnixMilli() > r.Until) {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,64 @@
+package interactions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	tests := []string{
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+	}
+	for _, expr := range tests {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// In 2026 New York springs forward at 2am on March 8 and falls back at
+	// 2am on November 1
+	tests := []struct {
+		name string
+		expr string
+		after time.Time
+		want time.Time
+	}{
+		{"step", "*/15 * * * *", time.Date(2026, 3, 11, 10, 7, 0, 0, loc), time.Date(2026, 3, 11, 10, 15, 0, 0, loc)},
+		{"seconds", "30 * * * * *", time.Date(2026, 3, 11, 10, 7, 30, 0, loc), time.Date(2026, 3, 11, 10, 8, 30, 0, loc)},
+		{"weekdays", "0 9 * * mon-fri", time.Date(2026, 3, 13, 10, 0, 0, 0, loc), time.Date(2026, 3, 16, 9, 0, 0, 0, loc)},
+		{"sunday as 7", "0 9 * * 7", time.Date(2026, 3, 11, 10, 0, 0, 0, loc), time.Date(2026, 3, 15, 9, 0, 0, 0, loc)},
+		{"either day field", "0 9 1 * fri", time.Date(2026, 3, 11, 10, 0, 0, 0, loc), time.Date(2026, 3, 13, 9, 0, 0, 0, loc)},
+		{"never", "0 0 31 2 *", time.Date(2026, 3, 11, 10, 0, 0, 0, loc), time.Time{}},
+		{"wall clock kept after spring forward", "0 9 * * *", time.Date(2026, 3, 7, 10, 0, 0, 0, loc), time.Date(2026, 3, 8, 9, 0, 0, 0, loc)},
+		{"skipped time fires after the jump", "30 2 * * *", time.Date(2026, 3, 7, 10, 0, 0, 0, loc), time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC)},
+		{"skipped time keeps its wall clock the next day", "30 2 * * *", time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC), time.Date(2026, 3, 9, 2, 30, 0, 0, loc)},
+		{"wall clock kept after fall back", "0 9 * * *", time.Date(2026, 10, 31, 10, 0, 0, 0, loc), time.Date(2026, 11, 1, 9, 0, 0, 0, loc)},
+		{"repeated time fires first", "30 1 * * *", time.Date(2026, 11, 1, 0, 0, 0, 0, loc), time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)},
+		{"repeated time fires once", "30 1 * * *", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), time.Date(2026, 11, 2, 1, 30, 0, 0, loc)},
+	}
+	for _, test := range tests {
+		schedule, err := parseCron(test.expr)
+		if err != nil {
+			t.Errorf("%s: parseCron(%q) error = %v", test.name, test.expr, err)
+			continue
+		}
+		if got := schedule.next(test.after, loc); !got.Equal(test.want) {
+			t.Errorf("%s: next(%v) = %v, want %v", test.name, test.after, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package interactions
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+	"github.com/bwmarrin/discordgo"
+	"github.com/anishmit/gobot/firebase"
+)
+
+// Jobs are taken from the queue this long before their time so that they can
+// be claimed and prepared before they are due
+const JOB_WAKE_LEAD = 2 * time.Second
+// The last part of the wait for a job's time is spent spinning instead of
+// sleeping, since timers can fire a few milliseconds late
+const JOB_SPIN_WAIT = 10 * time.Millisecond
+// Sends that fail for reasons other than the request being rejected are
+// retried with exponential backoff
+const DELIVERY_ATTEMPTS = 4
+const DELIVERY_RETRY_DELAY = time.Second
+// How far before a send started a message it created is looked for, to allow
+// for the difference between Discord's clock and ours
+const DELIVERY_LOOKUP_SKEW = 2 * time.Second
+// Weight of each new measurement in the delivery calibration
+const DELIVERY_CALIBRATION_WEIGHT = 0.2
+
+// deliveryCalibration estimates how early a message has to be sent for
+// Discord to create it at the target time. Discord's clock is read from the
+// snowflake of each created message: its offset from the midpoint of the
+// request estimates the difference between the clocks, and what remains of
+// the time from starting the request to the snowflake is the latency until
+// Discord creates the message. Both are kept under schedulerStats.
+type deliveryCalibration struct {
+	mutex sync.Mutex
+	loaded bool
+	// Discord's clock minus ours in milliseconds
+	ClockOffset float64 `json:"clockOffset"`
+	// Milliseconds from starting a request to the message being created,
+	// on Discord's clock
+	Latency float64 `json:"latency"`
+	Samples int `json:"samples"`
+	// Offset of the last message from its target in milliseconds
+	LastOffset int64 `json:"lastOffset"`
+}
+
+var calibration = &deliveryCalibration{}
+var schedulerStatsRef = firebase.DB.NewRef("schedulerStats")
+
+// load reads the stored calibration once
+func (c *deliveryCalibration) load(ctx context.Context) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.loaded {
+		return
+	}
+	if err := schedulerStatsRef.Get(ctx, c); err != nil {
+		log.Println("Error loading scheduler stats", err)
+		return
+	}
+	c.loaded = true
+}
+
+// lead returns how long before a target a request should be started. Before
+// any message has been measured half the gateway heartbeat latency is used.
+func (c *deliveryCalibration) lead(s *discordgo.Session) time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.Samples == 0 {
+		return s.HeartbeatLatency() / 2
+	}
+	return time.Duration((c.ClockOffset + c.Latency) * float64(time.Millisecond))
+}
+
+// record updates the calibration with a message created by a request that
+// started at start and returned at end, and returns the message's offset
+// from its target
+func (c *deliveryCalibration) record(ctx context.Context, message *discordgo.Message, target, start, end time.Time) (time.Duration, error) {
+	created, err := discordgo.SnowflakeTimestamp(message.ID)
+	if err != nil {
+		return 0, err
+	}
+	// Snowflakes only have millisecond precision
+	createdMs := float64(created.UnixMilli())
+	midpoint := float64(start.UnixMicro() + end.UnixMicro()) / 2000
+	clockOffset := createdMs - midpoint
+	latency := createdMs - clockOffset - float64(start.UnixMicro()) / 1000
+	offset := created.Sub(target)
+
+	c.mutex.Lock()
+	if c.Samples == 0 {
+		c.ClockOffset = clockOffset
+		c.Latency = latency
+	} else {
+		c.ClockOffset += DELIVERY_CALIBRATION_WEIGHT * (clockOffset - c.ClockOffset)
+		c.Latency += DELIVERY_CALIBRATION_WEIGHT * (latency - c.Latency)
+	}
+	c.Samples++
+	c.LastOffset = offset.Milliseconds()
+	stats := map[string]interface{}{
+		"clockOffset": c.ClockOffset,
+		"latency": c.Latency,
+		"samples": c.Samples,
+		"lastOffset": c.LastOffset,
+	}
+	c.mutex.Unlock()
+	return offset, schedulerStatsRef.Set(ctx, stats)
+}
+
+// warmConnection makes a request so that the connection to Discord is open
+// when a job is sent
+func warmConnection(s *discordgo.Session) {
+	if _, err := s.Gateway(); err != nil {
+		log.Println("Error warming connection", err)
+	}
+}
+
+// waitUntil sleeps until shortly before t and spins for the rest
+func waitUntil(t time.Time) {
+	if wait := time.Until(t) - JOB_SPIN_WAIT; wait > 0 {
+		time.Sleep(wait)
+	}
+	for time.Now().Before(t) {
+		runtime.Gosched()
+	}
+}
+
+// isPermanentDeliveryError reports whether Discord rejected a request in a way
+// that retrying won't fix, like a deleted channel or missing permissions.
+// Localized errors are the bot's own checks failing, which are final too.
+// Rate limits are retried by the session itself.
+func isPermanentDeliveryError(err error) bool {
+	var localized localizedError
+	if errors.As(err, &localized) {
+		return true
+	}
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Response == nil {
+		return false
+	}
+	status := restErr.Response.StatusCode
+	return status >= 400 && status < 500 && status != http.StatusTooManyRequests
+}
+
+// isUnsentDeliveryError reports whether a request failed without Discord
+// creating anything: the connection couldn't be made, or Discord answered
+// with a server error or a rate limit. Other failures like timeouts can
+// happen after the message was created.
+func isUnsentDeliveryError(err error) bool {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		status := restErr.Response.StatusCode
+		return status >= 500 || status == http.StatusTooManyRequests
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// findDeliveredMessage returns a message the bot created in a channel since
+// a send started, or nil if there is none
+func findDeliveredMessage(s *discordgo.Session, channelID string, since time.Time) (*discordgo.Message, error) {
+	minID, _ := snowflakeRange(since.Add(-DELIVERY_LOOKUP_SKEW))
+	messages, err := s.ChannelMessages(channelID, 100, "", strconv.FormatUint(minID - 1, 10), "")
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		if message.Author != nil && s.State.User != nil && message.Author.ID == s.State.User.ID {
+			return message, nil
+		}
+	}
+	return nil, nil
+}
+
+// describeDeliveryError returns the reason Discord gave for rejecting a
+// request, or the error itself
+func describeDeliveryError(locale discordgo.Locale, err error) string {
+	if e, ok := err.(localizedError); ok {
+		return translate(locale, e.Key, e.Args...)
+	}
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Message != "" {
+		return restErr.Message.Message
+	}
+	return err.Error()
+}
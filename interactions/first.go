@@ -2,110 +2,544 @@ package interactions
 
 import (
 	"context"
+	"encoding/json"
 	"log"
-	"firebase.google.com/go/v4/db"
 	"github.com/bwmarrin/discordgo"
 	"time"
 	"sort"
+	"sync"
 	"fmt"
 	"github.com/anishmit/gobot/firebase"
 )
 
 type TimePeriod struct {
-	Name string
-	Days int
-}	
+	Name string // Message key of the period's name
+	Days int // Number of days up to today in the period, 0 for all time
+}
 type FirstMessage struct {
 	Content string `json:"content"`
 	Date int64 `json:"date"`
 	MsgID string `json:"msgId"`
 	UserID string `json:"userId"`
+	// Milliseconds into the day when the message was recorded, so that
+	// rebuilds don't depend on the game's current day start and timezone
+	Time int64 `json:"time,omitempty"`
+}
+// FirstGame is the first message game of a channel, stored in the guild config
+type FirstGame struct {
+	// Empty to use the guild's timezone
+	Timezone string `json:"timezone,omitempty"`
+	// Minutes after midnight that days start at
+	DayStart int `json:"dayStart,omitempty"`
+	// React to the first message of each day
+	Reactions bool `json:"reactions,omitempty"`
+	// Post a summary of each day SummaryDelay minutes after it starts, with
+	// the job that posts them
+	Summary bool `json:"summary,omitempty"`
+	SummaryDelay int `json:"summaryDelay,omitempty"`
+	SummaryJobID string `json:"summaryJobId,omitempty"`
+	// Number of users placed each day, FIRST_DEFAULT_PLACES if 0
+	Places int `json:"places,omitempty"`
+	// What happens to placed messages edited within EditWindow seconds of
+	// being sent, or at any time if it's 0
+	EditPolicy string `json:"editPolicy,omitempty"`
+	EditWindow int `json:"editWindow,omitempty"`
+}
+type firstOptions struct {
+	Count *firstChannelOptions `option:"count" description:"Leaderboard for number of first messages"`
+	Time *firstChannelOptions `option:"time" description:"Leaderboard for fastest first messages"`
+	Streaks *firstChannelOptions `option:"streaks" description:"Leaderboard for streaks of days won in a row, with personal bests and averages"`
+	Podium *firstPodiumOptions `option:"podium" description:"Leaderboard for places, with points and average placement"`
+	Profile *firstProfileOptions `option:"profile" description:"First message record of a user"`
+	Enable *firstEnableOptions `option:"enable" description:"Start the first message game in a channel or change its settings"`
+	Announce *firstAnnounceOptions `option:"announce" description:"Choose how the winner of each day is announced"`
+	Edits *firstEditsOptions `option:"edits" description:"Choose what happens to placed messages that are edited"`
+	Void *firstVoidOptions `option:"void" description:"Take a day out of a game's records"`
+	Unvoid *firstUnvoidOptions `option:"unvoid" description:"Count a voided day again"`
+	Disable *firstChannelOptions `option:"disable" description:"Stop the first message game in a channel, keeping its data"`
+	Rebuild *firstChannelOptions `option:"rebuild" description:"Recompute the leaderboards of a game from its first messages"`
 }
-type FirstMessageWithTime struct {
-	Time int64
-	Date string
-	MsgID string
-	UserId string
+type firstChannelOptions struct {
+	Channel *discordgo.Channel `option:"channel" description:"Channel of the game, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+}
+type firstEnableOptions struct {
+	Channel *discordgo.Channel `option:"channel" description:"Channel of the game, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+	Timezone string `option:"timezone" description:"IANA timezone days are counted in, the server's by default" autocomplete:"true"`
+	DayStart string `option:"day_start" description:"Time days start at, like 04:00, midnight by default"`
+	Places int `option:"places" description:"Number of users placed each day, 3 by default" min:"1" max:"10"`
 }
 
-const SERVER_ID = "407302806241017866"
-const CHANNEL_ID = "407302806241017868"
+// The game used to only run in this channel, with its messages stored
+// directly under firstMessages/<date>
+const LEGACY_FIRST_GUILD_ID = "407302806241017866"
+const LEGACY_FIRST_CHANNEL_ID = "407302806241017868"
+const LEGACY_FIRST_TIMEZONE = "America/Detroit"
 var TIME_PERIODS = [5]TimePeriod{
-	{Name: "Today", Days: 1},
-	{Name: "Past Week", Days: 7},
-	{Name: "Past Month", Days: 30},
-	{Name: "Past Year", Days: 365},
-	{Name: "All Time", Days: 1e9},
+	{Name: "first.today", Days: 1},
+	{Name: "first.pastWeek", Days: 7},
+	{Name: "first.pastMonth", Days: 30},
+	{Name: "first.pastYear", Days: 365},
+	{Name: "first.allTime"},
 }
-var channelCreatedTime time.Time;
+var firstMessagesRef = firebase.DB.NewRef("firstMessages")
 
-func init() {
-	location, err := time.LoadLocation("America/Detroit")
+// elapsed returns how many milliseconds into its day a first message was.
+// Messages recorded before they kept their time use the game's clock now.
+func (message FirstMessage) elapsed(clock firstClock, date string) (int64, error) {
+	if message.Time != 0 {
+		return message.Time, nil
+	}
+	start, err := clock.start(date)
 	if err != nil {
-		log.Fatalln("Error loading location", err)
+		return 0, err
 	}
+	return message.Date - start.UnixMilli(), nil
+}
 
-	ctx := context.Background()
-	client, err := firebase.App.Database(ctx)
+// contains reports whether a date is in a period that ends today
+func (timePeriod TimePeriod) contains(date, today string) bool {
+	return timePeriod.Days == 0 || (date >= shiftDate(today, 1 - timePeriod.Days) && date <= today)
+}
+
+// firstClock divides time into the days of a game
+type firstClock struct {
+	loc *time.Location
+	dayStart int
+}
+
+// clock returns the clock of a game in a guild with a config
+func (game FirstGame) clock(config GuildConfig) firstClock {
+	timezone := game.Timezone
+	if timezone == "" {
+		timezone = config.Timezone
+	}
+	loc, err := time.LoadLocation(timezone)
 	if err != nil {
-		log.Fatalln("Error initializing database client", err)
+		log.Println("Error loading timezone", timezone, err)
+		loc = time.UTC
 	}
-	dbRef := client.NewRef("firstMessages")
+	return firstClock{loc, game.DayStart}
+}
 
-	Commands = append(Commands, &discordgo.ApplicationCommand{
-		Name:        "first",
-		Description: "Data about first messages",
-		Options: []*discordgo.ApplicationCommandOption{
-			{
-				Name: "count",
-				Description: "Leaderboard for number of first messages",
-				Type: discordgo.ApplicationCommandOptionSubCommand,
-			},
-			{
-				Name: "time",
-				Description: "Leaderboard for fastest first messages",
-				Type: discordgo.ApplicationCommandOptionSubCommand,
-			},
+// start returns when the day of a date starts
+func (c firstClock) start(date string) (time.Time, error) {
+	d, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, c.dayStart, 0, 0, c.loc), nil
+}
+
+// day returns the date of the day a time is in and when that day started
+func (c firstClock) day(t time.Time) (string, time.Time) {
+	local := t.In(c.loc)
+	year, month, day := local.Date()
+	start := time.Date(year, month, day, 0, c.dayStart, 0, 0, c.loc)
+	if t.Before(start) {
+		day--
+		start = time.Date(year, month, day, 0, c.dayStart, 0, 0, c.loc)
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Format(time.DateOnly), start
+}
+
+// firstGame returns the game of a channel and whether there is one
+func firstGame(guildID, channelID string) (FirstGame, bool) {
+	game, ok := getGuildConfig(guildID).FirstGames[channelID]
+	return game, ok
+}
+
+// userFirstMessageStats returns how many first messages a user has across the
+// games of a guild and how many milliseconds into its day their fastest one was
+func userFirstMessageStats(ctx context.Context, guildID, userID string) (int, int64, error) {
+	if guildID == "" {
+		return 0, 0, nil
+	}
+	var channelIDs map[string]interface{}
+	if err := firstStatsRef.Child(guildID).GetShallow(ctx, &channelIDs); err != nil {
+		return 0, 0, err
+	}
+	count := 0
+	fastest := int64(-1)
+	for channelID := range channelIDs {
+		var user *FirstUserStats
+		if err := firstStatsRef.Child(guildID).Child(channelID).Child("users").Child(userID).Get(ctx, &user); err != nil {
+			return 0, 0, err
+		}
+		if user == nil || user.Count == 0 {
+			continue
+		}
+		count += user.Count
+		if fastest < 0 || user.Best < fastest {
+			fastest = user.Best
+		}
+	}
+	return count, fastest, nil
+}
+
+// migrateFirstMessages moves the messages of the original game from
+// firstMessages/<date> to under its guild and channel, and sets up the game
+// with the timezone it used
+func migrateFirstMessages(ctx context.Context) error {
+	var keys map[string]interface{}
+	if err := firstMessagesRef.GetShallow(ctx, &keys); err != nil {
+		return err
+	}
+	legacy := false
+	for key := range keys {
+		if _, err := time.Parse(time.DateOnly, key); err == nil {
+			legacy = true
+			break
+		}
+	}
+	if !legacy {
+		return nil
+	}
+	var existing *FirstGame
+	if err := guildConfigsRef.Child(LEGACY_FIRST_GUILD_ID).Child("firstGames").Child(LEGACY_FIRST_CHANNEL_ID).Get(ctx, &existing); err != nil {
+		return err
+	}
+	if existing == nil {
+		if err := setFirstGame(ctx, LEGACY_FIRST_GUILD_ID, LEGACY_FIRST_CHANNEL_ID, &FirstGame{Timezone: LEGACY_FIRST_TIMEZONE, Reactions: true}); err != nil {
+			return err
+		}
+	}
+	var data map[string]json.RawMessage
+	if err := firstMessagesRef.Get(ctx, &data); err != nil {
+		return err
+	}
+	// Moving is one update so that a failure leaves the tree as it was
+	updates := map[string]interface{}{}
+	for key, value := range data {
+		if _, err := time.Parse(time.DateOnly, key); err != nil {
+			continue
+		}
+		updates[LEGACY_FIRST_GUILD_ID + "/" + LEGACY_FIRST_CHANNEL_ID + "/" + key] = value
+		updates[key] = nil
+	}
+	if err := firstMessagesRef.Update(ctx, updates); err != nil {
+		return err
+	}
+	log.Println("Migrated", len(updates) / 2, "first messages")
+	return nil
+}
+
+// respondFirst replies to a /first command only the user can see
+func respondFirst(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func handleFirstEnable(s *discordgo.Session, i *discordgo.InteractionCreate, options *firstEnableOptions) {
+	if !isGuildManager(i) {
+		respondOptionError(s, i, localizedError{"first.notManager", nil})
+		return
+	}
+	channelID := i.ChannelID
+	if options.Channel != nil {
+		channelID = options.Channel.ID
+	}
+	game, ok := firstGame(i.GuildID, channelID)
+	if !ok {
+		game = FirstGame{Reactions: true, Summary: true, SummaryDelay: FIRST_DEFAULT_SUMMARY_DELAY}
+	}
+	if options.Timezone != "" {
+		loc, err := parseTimezone(options.Timezone)
+		if err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+		game.Timezone = loc.String()
+	}
+	if options.DayStart != "" {
+		hour, minute, second, ms, ok := parseTimeOfDay(options.DayStart, true)
+		if !ok || second != 0 || ms != 0 {
+			respondOptionError(s, i, localizedError{"first.invalidDayStart", []any{options.DayStart}})
+			return
+		}
+		game.DayStart = hour * 60 + minute
+	}
+	if options.Places != 0 {
+		game.Places = options.Places
+	}
+	ctx := context.Background()
+	// The summary job follows the start of the day
+	oldJobID := game.SummaryJobID
+	if err := scheduleFirstSummary(ctx, i, channelID, &game); err != nil {
+		log.Println("Error scheduling first message summary", err)
+		respondOptionError(s, i, localizedError{"first.saveFailed", nil})
+		return
+	}
+	if err := saveFirstGame(ctx, i.GuildID, channelID, &game, oldJobID); err != nil {
+		log.Println("Error saving first message game", err)
+		respondOptionError(s, i, localizedError{"first.saveFailed", nil})
+		return
+	}
+	respondFirst(s, i, tr(i, "first.enabled", channelID, game.clock(getGuildConfig(i.GuildID)).loc.String(), game.DayStart / 60, game.DayStart % 60, game.places()))
+}
+
+func handleFirstAnnounce(s *discordgo.Session, i *discordgo.InteractionCreate, options *firstAnnounceOptions) {
+	if !isGuildManager(i) {
+		respondOptionError(s, i, localizedError{"first.notManager", nil})
+		return
+	}
+	channelID := i.ChannelID
+	if options.Channel != nil {
+		channelID = options.Channel.ID
+	}
+	game, ok := firstGame(i.GuildID, channelID)
+	if !ok {
+		respondOptionError(s, i, localizedError{"first.noGame", []any{channelID}})
+		return
+	}
+	game.Reactions = options.Reactions
+	game.Summary = options.Summary
+	game.SummaryDelay = options.SummaryDelay
+	ctx := context.Background()
+	oldJobID := game.SummaryJobID
+	if err := scheduleFirstSummary(ctx, i, channelID, &game); err != nil {
+		log.Println("Error scheduling first message summary", err)
+		respondOptionError(s, i, localizedError{"first.saveFailed", nil})
+		return
+	}
+	if err := saveFirstGame(ctx, i.GuildID, channelID, &game, oldJobID); err != nil {
+		log.Println("Error saving first message game", err)
+		respondOptionError(s, i, localizedError{"first.saveFailed", nil})
+		return
+	}
+	content := tr(i, "first.announceNone", channelID)
+	switch {
+	case game.Reactions && game.Summary:
+		content = tr(i, "first.announceBoth", channelID, game.SummaryDelay)
+	case game.Reactions:
+		content = tr(i, "first.announceReactions", channelID)
+	case game.Summary:
+		content = tr(i, "first.announceSummary", channelID, game.SummaryDelay)
+	}
+	respondFirst(s, i, content)
+}
+
+func handleFirstDisable(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string) {
+	if !isGuildManager(i) {
+		respondOptionError(s, i, localizedError{"first.notManager", nil})
+		return
+	}
+	game, ok := firstGame(i.GuildID, channelID)
+	if !ok {
+		respondOptionError(s, i, localizedError{"first.noGame", []any{channelID}})
+		return
+	}
+	if err := saveFirstGame(context.Background(), i.GuildID, channelID, nil, game.SummaryJobID); err != nil {
+		log.Println("Error saving first message game", err)
+		respondOptionError(s, i, localizedError{"first.saveFailed", nil})
+		return
+	}
+	respondFirst(s, i, tr(i, "first.disabled", channelID))
+}
+
+func handleFirstRebuild(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string) {
+	if !isGuildManager(i) {
+		respondOptionError(s, i, localizedError{"first.notManager", nil})
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
 		},
 	})
+	content := ""
+	if days, err := rebuildFirstStats(context.Background(), i.GuildID, channelID); err != nil {
+		log.Println("Error rebuilding first message stats", err)
+		content = tr(i, "first.rebuildFailed")
+	} else {
+		content = tr(i, "first.rebuilt", channelID, days)
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+	})
+}
+
+// Locks of the channels whose first messages are being recorded. A channel's
+// messages all arrive at the process of the shard that owns its guild, so
+// these serialize every change to a day's places and first message.
+var firstChannelLocks = map[string]*sync.Mutex{}
+var firstChannelLocksMutex sync.Mutex
+
+// lockFirstChannel locks a channel's first message records and returns the
+// function that unlocks them
+func lockFirstChannel(channelID string) func() {
+	firstChannelLocksMutex.Lock()
+	lock, ok := firstChannelLocks[channelID]
+	if !ok {
+		lock = &sync.Mutex{}
+		firstChannelLocks[channelID] = lock
+	}
+	firstChannelLocksMutex.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+// recordFirstEntry records a message's place in its day and, if it's first,
+// the day's first message, along with the aggregates of both
+func recordFirstEntry(ctx context.Context, guildID, channelID, date string, game FirstGame, start time.Time, message FirstMessage) (firstRecord, error) {
+	defer lockFirstChannel(channelID)()
+	if voided, err := isFirstDayVoided(ctx, guildID, channelID, date); err != nil {
+		return firstRecord{}, err
+	} else if voided {
+		rememberVoidedDay(channelID, date)
+		return firstRecord{}, nil
+	}
+	places := game.places()
+	// Messages that arrive after the summary can't change what it said
+	settled := game.summaryPosted(start, time.Now())
+	place, before, after, err := recordPlace(ctx, guildID, channelID, date, places, settled, FirstPlace{message.UserID, message.MsgID, message.Date, message.Time, places})
+	if err != nil || place == 0 {
+		return firstRecord{}, err
+	}
+	var record firstRecord
+	if place == 1 {
+		record, err = recordFirstMessage(ctx, guildID, channelID, date, message, !settled)
+		if err != nil {
+			// The place names a first message that wasn't recorded
+			if restored, err := restorePlace(ctx, guildID, channelID, date, message.MsgID, places, before); err != nil {
+				log.Println("Error restoring first message places", err)
+			} else {
+				after = restored
+			}
+		}
+	}
+	if err := recordPlaceStats(ctx, guildID, channelID, places, before, after); err != nil {
+		log.Println("Error recording first message place stats", err)
+	}
+	return record, err
+}
+
+func init() {
+	ctx := context.Background()
+
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
+		Name:        "first",
+		Description: "Data about first messages",
+		Options: commandOptions(firstOptions{}),
+		IntegrationTypes: GUILD_INTEGRATIONS,
+		Contexts: GUILD_CONTEXTS,
+	}))
 
 	CommandHandlers["first"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		var options firstOptions
+		if err := parseOptions(i.ApplicationCommandData(), &options); err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+		channelID := i.ChannelID
+		var channelOptions *firstChannelOptions
+		switch {
+		case options.Enable != nil:
+			handleFirstEnable(s, i, options.Enable)
+			return
+		case options.Announce != nil:
+			handleFirstAnnounce(s, i, options.Announce)
+			return
+		case options.Edits != nil:
+			handleFirstEdits(s, i, options.Edits)
+			return
+		case options.Void != nil:
+			handleFirstVoid(s, i, options.Void)
+			return
+		case options.Unvoid != nil:
+			handleFirstUnvoid(s, i, options.Unvoid)
+			return
+		case options.Count != nil:
+			channelOptions = options.Count
+		case options.Time != nil:
+			channelOptions = options.Time
+		case options.Streaks != nil:
+			channelOptions = options.Streaks
+		case options.Disable != nil:
+			channelOptions = options.Disable
+		case options.Rebuild != nil:
+			channelOptions = options.Rebuild
+		}
+		if channelOptions != nil && channelOptions.Channel != nil {
+			channelID = channelOptions.Channel.ID
+		}
+		if options.Profile != nil && options.Profile.Channel != nil {
+			channelID = options.Profile.Channel.ID
+		}
+		if options.Podium != nil && options.Podium.Channel != nil {
+			channelID = options.Podium.Channel.ID
+		}
+		if options.Disable != nil {
+			handleFirstDisable(s, i, channelID)
+			return
+		}
+		if options.Rebuild != nil {
+			handleFirstRebuild(s, i, channelID)
+			return
+		}
+		game, ok := firstGame(i.GuildID, channelID)
+		if !ok {
+			respondOptionError(s, i, localizedError{"first.noGame", []any{channelID}})
+			return
+		}
+		clock := game.clock(getGuildConfig(i.GuildID))
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 		})
 
-		var data map[string]FirstMessage
-		if err := dbRef.Get(ctx, &data); err != nil {
-			log.Println("Error reading from database", err)
-			return
-		}
-
-		options := i.ApplicationCommandData().Options
-
-		switch options[0].Name {
-		case "count":
+		locale := interactionLocale(i)
+		statsRef := firstStatsRef.Child(i.GuildID).Child(channelID)
+		switch {
+		case options.Profile != nil:
+			user := options.Profile.User
+			if user == nil {
+				user = invokingUser(i)
+			}
+			handleFirstProfile(s, i, channelID, clock, user)
+		case options.Podium != nil:
+			handleFirstPodium(s, i, channelID, options.Podium.Place)
+		case options.Count != nil:
 			curTime, err := discordgo.SnowflakeTimestamp(i.Interaction.ID)
 			if err != nil {
 				log.Println("Error getting interaction time", err)
 				return
 			}
-			curTime = curTime.In(location)
+			today, _ := clock.day(curTime)
+			longest := 0
+			for _, timePeriod := range TIME_PERIODS {
+				longest = max(longest, timePeriod.Days)
+			}
+			// Winners of the days in the longest period, leaving older days out
+			var winners map[string]string
+			if err := statsRef.Child("days").OrderByKey().StartAt(shiftDate(today, 1 - longest)).Get(ctx, &winners); err != nil {
+				log.Println("Error reading from database", err)
+				return
+			}
+			var users map[string]*FirstUserStats
+			if err := statsRef.Child("users").Get(ctx, &users); err != nil {
+				log.Println("Error reading from database", err)
+				return
+			}
 
-			daysSubtracted := 0
 			var timePeriodsData [len(TIME_PERIODS)]map[string]int
-			for i := range timePeriodsData {
-				timePeriodsData[i] = make(map[string]int)
-			}
-			for curTime.Year() != channelCreatedTime.Year() || curTime.YearDay() != channelCreatedTime.YearDay() {
-				if value, ok := data[curTime.Format(time.DateOnly)]; ok {
-					for i, timePeriod := range TIME_PERIODS {
-						if timePeriod.Days > daysSubtracted {
-							timePeriodsData[i][value.UserID]++
-						}
+			for j, timePeriod := range TIME_PERIODS {
+				timePeriodsData[j] = make(map[string]int)
+				if timePeriod.Days == 0 {
+					for userId, user := range users {
+						timePeriodsData[j][userId] = user.Count
+					}
+					continue
+				}
+				for date, userId := range winners {
+					if timePeriod.contains(date, today) {
+						timePeriodsData[j][userId]++
 					}
 				}
-				curTime = curTime.AddDate(0, 0, -1)
-				daysSubtracted++;
 			}
 
 			fields := make([]*discordgo.MessageEmbedField, 0, len(timePeriodsData))
@@ -120,96 +554,170 @@ func init() {
 					fieldValue += fmt.Sprintf("<@%s>: %d\n", userId, timePeriodData[userId])
 				}
 				fields = append(fields, &discordgo.MessageEmbedField{
-					Name: TIME_PERIODS[i].Name,
+					Name: translate(locale, TIME_PERIODS[i].Name),
 					Value: fieldValue,
 				})
 			}
-			
+
 			s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
 				Embeds: []*discordgo.MessageEmbed{
 					{
-						Title: "First Leaderboard (Count)",
+						Title: translate(locale, "first.countTitle"),
 						Color: 0xff4d01,
 						Fields: fields,
 					},
 				},
 			})
-		case "time":
-			firstMessages := make([]FirstMessageWithTime, 0, len(data))
-			for dateStr, firstMessage := range data {
-				if t, err := time.ParseInLocation(time.DateOnly, dateStr, location); err != nil {
-					log.Println("Error parsing location", err)
-				} else {
-					firstMessages = append(firstMessages, FirstMessageWithTime{
-						Time: firstMessage.Date - t.UnixMilli(),
-						Date: dateStr,
-						MsgID: firstMessage.MsgID,
-						UserId: firstMessage.UserID,
-					})
+		case options.Streaks != nil:
+			var users map[string]*FirstUserStats
+			if err := statsRef.Child("users").Get(ctx, &users); err != nil {
+				log.Println("Error reading from database", err)
+				return
+			}
+			today, _ := clock.day(time.Now())
+			userIds := make([]string, 0, len(users))
+			for userId := range users {
+				userIds = append(userIds, userId)
+			}
+			sort.Slice(userIds, func(i, j int) bool {
+				a, b := users[userIds[i]], users[userIds[j]]
+				if a.currentStreak(today) != b.currentStreak(today) {
+					return a.currentStreak(today) > b.currentStreak(today)
 				}
+				return a.LongestStreak > b.LongestStreak
+			})
+			var description string
+			for j, userId := range userIds[:min(15, len(userIds))] {
+				user := users[userId]
+				description += translate(locale, "first.streakEntry", j + 1, userId, user.currentStreak(today), user.LongestStreak, user.Best, user.average())
+			}
+			s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+				Embeds: []*discordgo.MessageEmbed{
+					{
+						Title: translate(locale, "first.streaksTitle"),
+						Color: 0xff4d01,
+						Description: description,
+					},
+				},
+			})
+		case options.Time != nil:
+			var firstMessages []FirstBestTime
+			if err := statsRef.Child("best").Get(ctx, &firstMessages); err != nil {
+				log.Println("Error reading from database", err)
+				return
 			}
-			sort.Slice(firstMessages, func(i, j int) bool { return firstMessages[i].Time < firstMessages[j].Time })
 			var description string
-			for i := 0; i < min(15, len(firstMessages)); i++ {
-				description += fmt.Sprintf(
-					"%d. <@%s>: **%d** ms on [%s](https://discord.com/channels/%s/%s/%s)\n", 
-					i + 1, 
-					firstMessages[i].UserId, 
-					firstMessages[i].Time, 
-					firstMessages[i].Date,
-					SERVER_ID,
-					CHANNEL_ID,
-					firstMessages[i].MsgID,
+			for j, firstMessage := range firstMessages {
+				description += translate(
+					locale,
+					"first.timeEntry",
+					j + 1,
+					firstMessage.UserID,
+					firstMessage.Time,
+					firstMessage.Date,
+					i.GuildID,
+					channelID,
+					firstMessage.MsgID,
 				)
 			}
+			// Times are from the start of the game's day, so say when that is
+			// for the caller
+			_, dayStart := clock.day(time.Now())
+			userLoc := userLocation(ctx, invokingUser(i).ID, i.GuildID)
 			s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
 				Embeds: []*discordgo.MessageEmbed{
 					{
-						Title: "First Leaderboard (Time)",
+						Title: translate(locale, "first.timeTitle"),
 						Color: 0xff4d01,
 						Description: description,
+						Footer: &discordgo.MessageEmbedFooter{
+							Text: translate(locale, "first.dayStart", dayStart.Format("15:04"), clock.loc.String(), dayStart.In(userLoc).Format("15:04"), userLoc.String()),
+						},
 					},
 				},
 			})
 		}
-		
-		
+	}
+	AutocompleteHandlers["first"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		focused := focusedOption(i.ApplicationCommandData().Options)
+		if focused == nil || focused.Name != "timezone" {
+			return
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{
+				Choices: timezoneChoices(focused.StringValue()),
+			},
+		})
 	}
 
 	MessageCreateHandlers = append(MessageCreateHandlers, func(s *discordgo.Session, m *discordgo.MessageCreate) {
-		if m.ChannelID == CHANNEL_ID {
-			curTime, err := discordgo.SnowflakeTimestamp(m.ID)
-			if err != nil {
-				log.Println("Error getting message time", err)
-				return
+		// Summaries and streak notices shouldn't take places, and neither
+		// should empty placeholders
+		if m.Author == nil || m.Author.Bot || !firstEligible(m.Message) {
+			return
+		}
+		game, ok := firstGame(m.GuildID, m.ChannelID)
+		if !ok {
+			return
+		}
+		curTime, err := discordgo.SnowflakeTimestamp(m.ID)
+		if err != nil {
+			log.Println("Error getting message time", err)
+			return
+		}
+		date, start := game.clock(getGuildConfig(m.GuildID)).day(curTime)
+		places := game.places()
+		if !couldPlace(m.ChannelID, date, m.Author.ID, curTime.UnixMilli(), places) {
+			return
+		}
+		firstMessage := FirstMessage{
+			Content: m.Content,
+			Date: curTime.UnixMilli(),
+			MsgID: m.ID,
+			UserID: m.Author.ID,
+			Time: curTime.Sub(start).Milliseconds(),
+		}
+		record, err := recordFirstEntry(ctx, m.GuildID, m.ChannelID, date, game, start, firstMessage)
+		if err != nil {
+			log.Println("Error recording first message", err)
+			return
+		}
+		if !record.Recorded {
+			return
+		}
+		if game.Reactions {
+			if err := s.MessageReactionAdd(m.ChannelID, m.ID, FIRST_WINNER_REACTION); err != nil {
+				log.Println("Error reacting to first message", err)
 			}
-			curTime = curTime.In(location)
-			dbRef.Child(curTime.Format(time.DateOnly)).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
-				var firstMessage FirstMessage
-				value.Unmarshal(&firstMessage)
-				if firstMessage.MsgID == "" || firstMessage.Date > curTime.UnixMilli() {
-					return FirstMessage{
-						Content: m.Content,
-						Date: curTime.UnixMilli(),
-						MsgID: m.ID,
-						UserID: m.Author.ID,
-					}, nil
-				} else {
-					return firstMessage, nil
+			if record.Replaced {
+				if err := s.MessageReactionRemove(m.ChannelID, record.ReplacedMsgID, FIRST_WINNER_REACTION, "@me"); err != nil {
+					log.Println("Error removing first message reaction", err)
 				}
+			}
+		}
+		if record.BrokenStreak >= FIRST_STREAK_NOTICE_MIN {
+			locale := guildLocale(s, m.GuildID)
+			content := translate(locale, "first.streakEnded", record.BrokenUserID, record.BrokenStreak)
+			if record.BrokenUserID != m.Author.ID {
+				content = translate(locale, "first.streakBroken", m.Author.ID, record.BrokenUserID, record.BrokenStreak)
+			}
+			_, err := s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+				Content: content,
+				AllowedMentions: &discordgo.MessageAllowedMentions{},
 			})
+			if err != nil {
+				log.Println("Error sending streak notice", err)
+			}
 		}
 	})
 
-	ReadyHandlers = append(ReadyHandlers, func(s *discordgo.Session, r *discordgo.Ready) {
-		channel, err := s.Channel(CHANNEL_ID); 
-		if (err != nil) {
-			log.Fatalln("Error getting channel", err)
+	// The migration sets up the legacy game in the guild config, so it runs
+	// before any process caches that config from its guild create
+	StartupTasks = append(StartupTasks, migrateFirstMessages)
+	LeaderJobs["firstStats"] = func(ctx context.Context, s *discordgo.Session) {
+		if err := rebuildOutdatedFirstStats(ctx); err != nil {
+			log.Println("Error building missing first message stats", err)
 		}
-		channelCreatedTime, err = discordgo.SnowflakeTimestamp(channel.ID)
-		if err != nil {
-			log.Fatalln("Error getting channel created time", err)
-		}
-		channelCreatedTime = channelCreatedTime.In(location)
-	})
-}
\ No newline at end of file
+	}
+}
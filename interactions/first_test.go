@@ -0,0 +1,43 @@
+package interactions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFirstClockDay(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Days start at 4am
+	clock := firstClock{loc, 240}
+	tests := []struct {
+		t time.Time
+		date string
+		start time.Time
+	}{
+		{time.Date(2026, 3, 11, 4, 0, 0, 0, loc), "2026-03-11", time.Date(2026, 3, 11, 4, 0, 0, 0, loc)},
+		{time.Date(2026, 3, 11, 23, 59, 0, 0, loc), "2026-03-11", time.Date(2026, 3, 11, 4, 0, 0, 0, loc)},
+		{time.Date(2026, 3, 12, 3, 59, 59, 999, loc), "2026-03-11", time.Date(2026, 3, 11, 4, 0, 0, 0, loc)},
+		// Before the day starts on the first of a month or year
+		{time.Date(2026, 3, 1, 2, 0, 0, 0, loc), "2026-02-28", time.Date(2026, 2, 28, 4, 0, 0, 0, loc)},
+		{time.Date(2026, 1, 1, 0, 30, 0, 0, loc), "2025-12-31", time.Date(2025, 12, 31, 4, 0, 0, 0, loc)},
+		// Times in other zones are placed on the game's clock
+		{time.Date(2026, 3, 11, 7, 30, 0, 0, time.UTC), "2026-03-10", time.Date(2026, 3, 10, 4, 0, 0, 0, loc)},
+		{time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC), "2026-03-11", time.Date(2026, 3, 11, 4, 0, 0, 0, loc)},
+		// Across DST changes, the day before has 23 or 25 hours
+		{time.Date(2026, 3, 8, 3, 30, 0, 0, loc), "2026-03-07", time.Date(2026, 3, 7, 4, 0, 0, 0, loc)},
+		{time.Date(2026, 3, 8, 4, 0, 0, 0, loc), "2026-03-08", time.Date(2026, 3, 8, 4, 0, 0, 0, loc)},
+		{time.Date(2026, 11, 1, 3, 59, 0, 0, loc), "2026-10-31", time.Date(2026, 10, 31, 4, 0, 0, 0, loc)},
+	}
+	for _, test := range tests {
+		date, start := clock.day(test.t)
+		if date != test.date || !start.Equal(test.start) {
+			t.Errorf("day(%v) = %s, %v, want %s, %v", test.t, date, start, test.date, test.start)
+		}
+		if start, err := clock.start(date); err != nil || !start.Equal(test.start) {
+			t.Errorf("start(%s) = %v, %v, want %v", date, start, err, test.start)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package interactions
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"firebase.google.com/go/v4/db"
+	"github.com/anishmit/gobot/firebase"
+	"github.com/bwmarrin/discordgo"
+)
+
+// What happens to a placed message that's edited
+const (
+	FIRST_EDITS_IGNORE = ""
+	FIRST_EDITS_FLAG = "flag"
+	FIRST_EDITS_DISQUALIFY = "disqualify"
+)
+
+// FirstVoids are what managers and the edit policy took out of a day, stored
+// under firstVoids/<guild>/<channel>/<date>
+type FirstVoids struct {
+	// The whole day doesn't count
+	Day bool `json:"day,omitempty"`
+	// IDs of disqualified messages
+	Messages map[string]bool `json:"messages,omitempty"`
+	// IDs of edited messages that were flagged
+	Flagged map[string]bool `json:"flagged,omitempty"`
+}
+
+type firstEditsOptions struct {
+	Policy string `option:"policy" description:"What happens to a placed message that's edited" choices:"ignore,flag,disqualify" required:"true"`
+	Window int `option:"window" description:"Only edits this many seconds after the message count, any edit if 0" default:"0" min:"0" max:"86400"`
+	Channel *discordgo.Channel `option:"channel" description:"Channel of the game, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+}
+
+type firstVoidOptions struct {
+	Date string `option:"date" description:"Day to void, like 2024-01-31" required:"true"`
+	Channel *discordgo.Channel `option:"channel" description:"Channel of the game, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+}
+
+type firstUnvoidOptions struct {
+	Date string `option:"date" description:"Voided day to count again, like 2024-01-31" required:"true"`
+	Channel *discordgo.Channel `option:"channel" description:"Channel of the game, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+}
+
+var firstVoidsRef = firebase.DB.NewRef("firstVoids")
+
+// firstEligible reports whether a message can place, which placeholders with
+// nothing in them can't
+func firstEligible(message *discordgo.Message) bool {
+	return strings.TrimSpace(message.Content) != "" || len(message.Attachments) > 0 || len(message.StickerItems) > 0
+}
+
+// placeIndex returns the index of a message in the places of a day, or -1
+func placeIndex(places []FirstPlace, msgID string) int {
+	for j, place := range places {
+		if place.MsgID == msgID {
+			return j
+		}
+	}
+	return -1
+}
+
+// refillFirstPlaces recomputes the places of a day from the channel's history
+// after a placed message was deleted or disqualified or the day was unvoided,
+// and moves first place to the new winner. History is read a page at a time
+// until the day ends or its places are full. deletedID is the message that
+// was deleted, if any, which has no reaction left to remove.
+func refillFirstPlaces(ctx context.Context, s *discordgo.Session, guildID, channelID, date, deletedID string) error {
+	defer lockFirstChannel(channelID)()
+	config, err := loadGuildConfig(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	game, ok := config.FirstGames[channelID]
+	if !ok {
+		return nil
+	}
+	clock := game.clock(config)
+	start, err := clock.start(date)
+	if err != nil {
+		return err
+	}
+	end := start.AddDate(0, 0, 1)
+	var voids FirstVoids
+	if err := firstVoidsRef.Child(guildID).Child(channelID).Child(date).Get(ctx, &voids); err != nil {
+		return err
+	}
+	if voids.Day {
+		return nil
+	}
+	dayRef := firstPlacesRef.Child(guildID).Child(channelID).Child(date)
+	var old []FirstPlace
+	if err := dayRef.Get(ctx, &old); err != nil {
+		return err
+	}
+	var oldWinner *FirstMessage
+	if err := firstMessagesRef.Child(guildID).Child(channelID).Child(date).Get(ctx, &oldWinner); err != nil {
+		return err
+	}
+
+	n := dayPlaces(old, game.places())
+	var places []FirstPlace
+	contents := map[string]string{}
+	seen := map[string]bool{}
+	minID, _ := snowflakeRange(start)
+	// Messages after an ID start from the oldest one
+	afterID := strconv.FormatUint(minID - 1, 10)
+history:
+	for len(places) < n {
+		messages, err := s.ChannelMessages(channelID, 100, "", afterID, "")
+		if err != nil {
+			return err
+		}
+		sort.Slice(messages, func(a, b int) bool {
+			idA, _ := strconv.ParseUint(messages[a].ID, 10, 64)
+			idB, _ := strconv.ParseUint(messages[b].ID, 10, 64)
+			return idA < idB
+		})
+		for _, message := range messages {
+			t, err := discordgo.SnowflakeTimestamp(message.ID)
+			if err != nil || !t.Before(end) || len(places) == n {
+				break history
+			}
+			if voids.Messages[message.ID] || message.Author == nil || message.Author.Bot || seen[message.Author.ID] || !firstEligible(message) {
+				continue
+			}
+			seen[message.Author.ID] = true
+			contents[message.ID] = message.Content
+			places = append(places, FirstPlace{message.Author.ID, message.ID, t.UnixMilli(), t.Sub(start).Milliseconds(), n})
+		}
+		if len(messages) < 100 {
+			break
+		}
+		afterID = messages[len(messages) - 1].ID
+	}
+
+	// Places recorded while history was read are kept
+	known := map[string]bool{}
+	for _, place := range old {
+		known[place.MsgID] = true
+	}
+	var before, after []FirstPlace
+	err = dayRef.Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var current []FirstPlace
+		if err := value.Unmarshal(&current); err != nil {
+			return nil, err
+		}
+		before = current
+		merged := append([]FirstPlace(nil), places...)
+		for _, place := range current {
+			if !known[place.MsgID] && !voids.Messages[place.MsgID] {
+				merged = append(merged, place)
+			}
+		}
+		sort.SliceStable(merged, func(a, b int) bool { return merged[a].Date < merged[b].Date })
+		after = nil
+		placed := map[string]bool{}
+		for _, place := range merged {
+			if placed[place.UserID] || len(after) == n {
+				continue
+			}
+			placed[place.UserID] = true
+			after = append(after, place)
+		}
+		return after, nil
+	})
+	if err != nil {
+		return err
+	}
+	rememberPlaces(channelID, date, after)
+	if err := recordPlaceStats(ctx, guildID, channelID, n, before, after); err != nil {
+		return err
+	}
+
+	var winner *FirstMessage
+	if len(after) > 0 {
+		winner = &FirstMessage{
+			Content: contents[after[0].MsgID],
+			Date: after[0].Date,
+			MsgID: after[0].MsgID,
+			UserID: after[0].UserID,
+			Time: after[0].Time,
+		}
+	}
+	if (winner == nil && oldWinner == nil) || (winner != nil && oldWinner != nil && winner.MsgID == oldWinner.MsgID) {
+		return nil
+	}
+	if err := changeFirstWinner(ctx, guildID, channelID, date, oldWinner, winner, nil); err != nil {
+		return err
+	}
+	if game.Reactions {
+		if oldWinner != nil && oldWinner.MsgID != deletedID {
+			if err := s.MessageReactionRemove(channelID, oldWinner.MsgID, FIRST_WINNER_REACTION, "@me"); err != nil {
+				log.Println("Error removing first message reaction", err)
+			}
+		}
+		if winner != nil {
+			if err := s.MessageReactionAdd(channelID, winner.MsgID, FIRST_WINNER_REACTION); err != nil {
+				log.Println("Error reacting to first message", err)
+			}
+		}
+	}
+	return nil
+}
+
+// markFirstMessage marks a message of a day as flagged or disqualified, with
+// kind "flagged" or "messages", and reports whether it wasn't already, so that
+// each message is only handled once
+func markFirstMessage(ctx context.Context, guildID, channelID, date, kind, msgID string) (bool, error) {
+	var marked bool
+	err := firstVoidsRef.Child(guildID).Child(channelID).Child(date).Child(kind).Child(msgID).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var set bool
+		if err := value.Unmarshal(&set); err != nil {
+			return nil, err
+		}
+		marked = !set
+		return true, nil
+	})
+	return marked, err
+}
+
+// placedDay returns the date of the day a message in a game is from and its
+// places, with ok false if the message didn't place
+func placedDay(ctx context.Context, guildID, channelID, msgID string) (string, []FirstPlace, bool, error) {
+	game, ok := firstGame(guildID, channelID)
+	if !ok {
+		return "", nil, false, nil
+	}
+	t, err := discordgo.SnowflakeTimestamp(msgID)
+	if err != nil {
+		return "", nil, false, err
+	}
+	date, _ := game.clock(getGuildConfig(guildID)).day(t)
+	var places []FirstPlace
+	if err := firstPlacesRef.Child(guildID).Child(channelID).Child(date).Get(ctx, &places); err != nil {
+		return "", nil, false, err
+	}
+	return date, places, placeIndex(places, msgID) >= 0, nil
+}
+
+// isFirstDayVoided reports whether managers voided a day
+func isFirstDayVoided(ctx context.Context, guildID, channelID, date string) (bool, error) {
+	var voided bool
+	err := firstVoidsRef.Child(guildID).Child(channelID).Child(date).Child("day").Get(ctx, &voided)
+	return voided, err
+}
+
+func handleFirstEdits(s *discordgo.Session, i *discordgo.InteractionCreate, options *firstEditsOptions) {
+	if !isGuildManager(i) {
+		respondOptionError(s, i, localizedError{"first.notManager", nil})
+		return
+	}
+	channelID := i.ChannelID
+	if options.Channel != nil {
+		channelID = options.Channel.ID
+	}
+	game, ok := firstGame(i.GuildID, channelID)
+	if !ok {
+		respondOptionError(s, i, localizedError{"first.noGame", []any{channelID}})
+		return
+	}
+	game.EditPolicy = options.Policy
+	if game.EditPolicy == "ignore" {
+		game.EditPolicy = FIRST_EDITS_IGNORE
+	}
+	game.EditWindow = options.Window
+	if err := setFirstGame(context.Background(), i.GuildID, channelID, &game); err != nil {
+		log.Println("Error saving first message game", err)
+		respondOptionError(s, i, localizedError{"first.saveFailed", nil})
+		return
+	}
+	content := tr(i, "first.editsIgnore", channelID)
+	switch {
+	case game.EditPolicy != FIRST_EDITS_IGNORE && game.EditWindow > 0:
+		content = tr(i, "first.editsWindow", channelID, game.EditWindow, tr(i, "first.edits." + game.EditPolicy))
+	case game.EditPolicy != FIRST_EDITS_IGNORE:
+		content = tr(i, "first.editsAny", channelID, tr(i, "first.edits." + game.EditPolicy))
+	}
+	respondFirst(s, i, content)
+}
+
+func handleFirstVoid(s *discordgo.Session, i *discordgo.InteractionCreate, options *firstVoidOptions) {
+	if !isGuildManager(i) {
+		respondOptionError(s, i, localizedError{"first.notManager", nil})
+		return
+	}
+	channelID := i.ChannelID
+	if options.Channel != nil {
+		channelID = options.Channel.ID
+	}
+	game, ok := firstGame(i.GuildID, channelID)
+	if !ok {
+		respondOptionError(s, i, localizedError{"first.noGame", []any{channelID}})
+		return
+	}
+	if _, err := time.Parse(time.DateOnly, options.Date); err != nil {
+		respondOptionError(s, i, localizedError{"first.invalidDate", []any{options.Date}})
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	ctx := context.Background()
+	content := tr(i, "first.voided", options.Date, channelID)
+	defer lockFirstChannel(channelID)()
+	var winner *FirstMessage
+	var places []FirstPlace
+	if err := firstMessagesRef.Child(i.GuildID).Child(channelID).Child(options.Date).Get(ctx, &winner); err != nil {
+		log.Println("Error reading from database", err)
+		content = tr(i, "first.voidFailed")
+	} else if err := firstPlacesRef.Child(i.GuildID).Child(channelID).Child(options.Date).Get(ctx, &places); err != nil {
+		log.Println("Error reading from database", err)
+		content = tr(i, "first.voidFailed")
+	} else if err := changeFirstWinner(ctx, i.GuildID, channelID, options.Date, winner, nil, map[string]interface{}{
+		"firstVoids/" + i.GuildID + "/" + channelID + "/" + options.Date + "/day": true,
+		"firstPlaces/" + i.GuildID + "/" + channelID + "/" + options.Date: nil,
+	}); err != nil {
+		log.Println("Error voiding first message day", err)
+		content = tr(i, "first.voidFailed")
+	} else {
+		rememberVoidedDay(channelID, options.Date)
+		if err := recordPlaceStats(ctx, i.GuildID, channelID, game.places(), places, nil); err != nil {
+			log.Println("Error recording first message place stats", err)
+		}
+		if winner != nil && game.Reactions {
+			if err := s.MessageReactionRemove(channelID, winner.MsgID, FIRST_WINNER_REACTION, "@me"); err != nil {
+				log.Println("Error removing first message reaction", err)
+			}
+		}
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+	})
+}
+
+// handleFirstUnvoid counts a voided day again, placing its messages from the
+// channel's history
+func handleFirstUnvoid(s *discordgo.Session, i *discordgo.InteractionCreate, options *firstUnvoidOptions) {
+	if !isGuildManager(i) {
+		respondOptionError(s, i, localizedError{"first.notManager", nil})
+		return
+	}
+	channelID := i.ChannelID
+	if options.Channel != nil {
+		channelID = options.Channel.ID
+	}
+	if _, ok := firstGame(i.GuildID, channelID); !ok {
+		respondOptionError(s, i, localizedError{"first.noGame", []any{channelID}})
+		return
+	}
+	if _, err := time.Parse(time.DateOnly, options.Date); err != nil {
+		respondOptionError(s, i, localizedError{"first.invalidDate", []any{options.Date}})
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	ctx := context.Background()
+	content := tr(i, "first.unvoided", options.Date, channelID)
+	if err := firstVoidsRef.Child(i.GuildID).Child(channelID).Child(options.Date).Child("day").Delete(ctx); err != nil {
+		log.Println("Error unvoiding first message day", err)
+		content = tr(i, "first.unvoidFailed")
+	} else if err := refillFirstPlaces(ctx, s, i.GuildID, channelID, options.Date, ""); err != nil {
+		log.Println("Error placing unvoided first message day", err)
+		content = tr(i, "first.unvoidFailed")
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+	})
+}
+
+func init() {
+	ctx := context.Background()
+
+	MessageDeleteHandlers = append(MessageDeleteHandlers, func(s *discordgo.Session, m *discordgo.MessageDelete) {
+		date, _, placed, err := placedDay(ctx, m.GuildID, m.ChannelID, m.ID)
+		if err != nil {
+			log.Println("Error reading from database", err)
+			return
+		}
+		if !placed {
+			return
+		}
+		if err := refillFirstPlaces(ctx, s, m.GuildID, m.ChannelID, date, m.ID); err != nil {
+			log.Println("Error replacing deleted first message", err)
+		}
+	})
+
+	MessageUpdateHandlers = append(MessageUpdateHandlers, func(s *discordgo.Session, m *discordgo.MessageUpdate) {
+		// Updates without an edit time are embeds being added
+		if m.EditedTimestamp == nil || m.Author == nil || m.Author.Bot {
+			return
+		}
+		game, ok := firstGame(m.GuildID, m.ChannelID)
+		if !ok || game.EditPolicy == FIRST_EDITS_IGNORE {
+			return
+		}
+		createdTime, err := discordgo.SnowflakeTimestamp(m.ID)
+		if err != nil {
+			log.Println("Error getting message time", err)
+			return
+		}
+		after := m.EditedTimestamp.Sub(createdTime)
+		if game.EditWindow > 0 && after > time.Duration(game.EditWindow) * time.Second {
+			return
+		}
+		date, places, placed, err := placedDay(ctx, m.GuildID, m.ChannelID, m.ID)
+		if err != nil {
+			log.Println("Error reading from database", err)
+			return
+		}
+		if !placed {
+			return
+		}
+		locale := guildLocale(s, m.GuildID)
+		place := placeIndex(places, m.ID) + 1
+		content := translate(locale, "first.editFlagged", m.Author.ID, place, date, int(after.Seconds()))
+		kind := "flagged"
+		if game.EditPolicy == FIRST_EDITS_DISQUALIFY {
+			kind = "messages"
+		}
+		// Each edit of a message comes here, but it's only handled once
+		if marked, err := markFirstMessage(ctx, m.GuildID, m.ChannelID, date, kind, m.ID); err != nil {
+			log.Println("Error marking edited first message", err)
+			return
+		} else if !marked {
+			return
+		}
+		if game.EditPolicy == FIRST_EDITS_DISQUALIFY {
+			if err := refillFirstPlaces(ctx, s, m.GuildID, m.ChannelID, date, ""); err != nil {
+				log.Println("Error replacing disqualified first message", err)
+			}
+			content = translate(locale, "first.editDisqualified", m.Author.ID, place, date, int(after.Seconds()))
+		}
+		_, err = s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+			Content: content,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+			Reference: m.Reference(),
+		})
+		if err != nil {
+			log.Println("Error sending edit notice", err)
+		}
+	})
+}
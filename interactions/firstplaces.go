@@ -0,0 +1,339 @@
+package interactions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"firebase.google.com/go/v4/db"
+	"github.com/anishmit/gobot/firebase"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Number of places recorded each day by default
+const FIRST_DEFAULT_PLACES = 3
+var FIRST_PLACE_MEDALS = []string{"🥇", "🥈", "🥉"}
+
+// FirstPlace is one of the first users of a day, stored in a list under
+// firstPlaces/<guild>/<channel>/<date> that's sorted by time. Each user has
+// at most one place, for their earliest message.
+type FirstPlace struct {
+	UserID string `json:"userId"`
+	MsgID string `json:"msgId"`
+	Date int64 `json:"date"`
+	// Milliseconds into the day
+	Time int64 `json:"time"`
+	// Number of places the day had, which stays the same if the game's
+	// changes later so that its points do too
+	Of int `json:"of,omitempty"`
+}
+
+type FirstPlaceStats struct {
+	// Number of days the user finished in each place, first place first
+	Places []int `json:"places"`
+	// Places are worth the number of places in the game for first, one less
+	// for second and so on
+	Points int `json:"points"`
+	// Sum of the user's places, for the average
+	PlaceSum int `json:"placeSum"`
+	Days int `json:"days"`
+}
+
+type firstPodiumOptions struct {
+	Place int `option:"place" description:"Sort by how often users finished in this place instead of by points" min:"1" max:"10"`
+	Channel *discordgo.Channel `option:"channel" description:"Channel of the game, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+}
+
+// Places of the latest day of each channel seen by this process, so that
+// messages that can't place skip the transaction
+type latestPlaces struct {
+	date string
+	places []FirstPlace
+	// Managers voided the day
+	voided bool
+}
+
+var latestPlacesByChannel = map[string]latestPlaces{}
+var latestPlacesMutex sync.Mutex
+var firstPlacesRef = firebase.DB.NewRef("firstPlaces")
+
+// places returns the number of places a game records each day
+func (game FirstGame) places() int {
+	if game.Places <= 0 {
+		return FIRST_DEFAULT_PLACES
+	}
+	return game.Places
+}
+
+// dayPlaces returns the number of places a day has, or n for days recorded
+// before days kept their own
+func dayPlaces(places []FirstPlace, n int) int {
+	if len(places) > 0 && places[0].Of > 0 {
+		return places[0].Of
+	}
+	return n
+}
+
+// average returns a user's average place
+func (user *FirstPlaceStats) average() float64 {
+	if user.Days == 0 {
+		return 0
+	}
+	return float64(user.PlaceSum) / float64(user.Days)
+}
+
+// addPlaces counts the places of a day, or takes them back if sign is -1.
+// Days without their own number of places are worth points for n.
+func (stats *FirstStats) addPlaces(places []FirstPlace, n int, sign int) {
+	n = dayPlaces(places, n)
+	if stats.Places == nil {
+		stats.Places = map[string]*FirstPlaceStats{}
+	}
+	for j, place := range places[:min(n, len(places))] {
+		user := stats.Places[place.UserID]
+		if user == nil {
+			user = &FirstPlaceStats{}
+			stats.Places[place.UserID] = user
+		}
+		for len(user.Places) <= j {
+			user.Places = append(user.Places, 0)
+		}
+		user.Places[j] += sign
+		user.Points += sign * (n - j)
+		user.PlaceSum += sign * (j + 1)
+		user.Days += sign
+		if user.Days <= 0 {
+			delete(stats.Places, place.UserID)
+		}
+	}
+}
+
+// couldPlace reports whether a user's message might place in its day, going
+// by the latest places this process has seen in its channel. Messages for a
+// day before the latest one can't since that day is over.
+func couldPlace(channelID, date, userID string, t int64, n int) bool {
+	latestPlacesMutex.Lock()
+	defer latestPlacesMutex.Unlock()
+	latest, ok := latestPlacesByChannel[channelID]
+	if !ok || date > latest.date {
+		return true
+	}
+	if date < latest.date || latest.voided {
+		return false
+	}
+	for _, place := range latest.places {
+		if place.UserID == userID && place.Date <= t {
+			return false
+		}
+	}
+	return len(latest.places) < dayPlaces(latest.places, n) || t < latest.places[len(latest.places) - 1].Date
+}
+
+func rememberPlaces(channelID, date string, places []FirstPlace) {
+	latestPlacesMutex.Lock()
+	defer latestPlacesMutex.Unlock()
+	if latest, ok := latestPlacesByChannel[channelID]; ok && latest.date > date {
+		return
+	}
+	latestPlacesByChannel[channelID] = latestPlaces{date, places, false}
+}
+
+func rememberVoidedDay(channelID, date string) {
+	latestPlacesMutex.Lock()
+	defer latestPlacesMutex.Unlock()
+	if latest, ok := latestPlacesByChannel[channelID]; ok && latest.date > date {
+		return
+	}
+	latestPlacesByChannel[channelID] = latestPlaces{date, nil, true}
+}
+
+// recordPlace adds a message to the places of its day if it's among the first
+// n users' earliest messages, or as many as the day already has. If settled,
+// the places already taken are kept and the message can only take the next
+// one. It returns the message's place,
+// 0 if it didn't place, and the places of the day before and after.
+func recordPlace(ctx context.Context, guildID, channelID, date string, n int, settled bool, entry FirstPlace) (int, []FirstPlace, []FirstPlace, error) {
+	var place int
+	var before, after []FirstPlace
+	err := firstPlacesRef.Child(guildID).Child(channelID).Child(date).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var places []FirstPlace
+		if err := value.Unmarshal(&places); err != nil {
+			return nil, err
+		}
+		before = append([]FirstPlace(nil), places...)
+		place, after = placeEntry(places, n, settled, entry)
+		return after, nil
+	})
+	if err == nil {
+		rememberPlaces(channelID, date, after)
+	}
+	return place, before, after, err
+}
+
+// placeEntry adds a message to the places of its day as recordPlace does and
+// returns its place, 0 if it didn't place, and the places after
+func placeEntry(places []FirstPlace, n int, settled bool, entry FirstPlace) (int, []FirstPlace) {
+	dayN := dayPlaces(places, n)
+	entry.Of = dayN
+	kept := places
+	for j, existing := range kept {
+		if existing.UserID != entry.UserID {
+			continue
+		}
+		if existing.Date <= entry.Date || settled {
+			return 0, places
+		}
+		// The user's earlier message arrived after a later one
+		kept = append(kept[:j:j], kept[j + 1:]...)
+		break
+	}
+	index := sort.Search(len(kept), func(j int) bool { return kept[j].Date > entry.Date })
+	if index >= dayN || (settled && index < len(kept)) {
+		return 0, places
+	}
+	kept = append(kept[:index:index], append([]FirstPlace{entry}, kept[index:]...)...)
+	return index + 1, kept[:min(len(kept), dayN)]
+}
+
+// restorePlace takes a message recorded by recordPlace back out of the places
+// of its day and puts back the places it displaced from before. It returns
+// the places of the day after.
+func restorePlace(ctx context.Context, guildID, channelID, date, msgID string, n int, before []FirstPlace) ([]FirstPlace, error) {
+	var after []FirstPlace
+	err := firstPlacesRef.Child(guildID).Child(channelID).Child(date).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var places []FirstPlace
+		if err := value.Unmarshal(&places); err != nil {
+			return nil, err
+		}
+		dayN := dayPlaces(places, dayPlaces(before, n))
+		placed := map[string]bool{}
+		var restored []FirstPlace
+		for _, place := range places {
+			if place.MsgID != msgID {
+				restored = append(restored, place)
+				placed[place.UserID] = true
+			}
+		}
+		for _, place := range before {
+			if !placed[place.UserID] {
+				restored = append(restored, place)
+				placed[place.UserID] = true
+			}
+		}
+		sort.SliceStable(restored, func(a, b int) bool { return restored[a].Date < restored[b].Date })
+		after = restored[:min(len(restored), dayN)]
+		return after, nil
+	})
+	if err == nil {
+		rememberPlaces(channelID, date, after)
+	}
+	return after, err
+}
+
+// recordPlaceStats updates the place aggregates of the users whose places
+// changed in a day, each in its own transaction
+func recordPlaceStats(ctx context.Context, guildID, channelID string, n int, before, after []FirstPlace) error {
+	n = dayPlaces(after, dayPlaces(before, n))
+	changed := map[string]bool{}
+	for j, place := range before[:min(n, len(before))] {
+		if j >= len(after) || after[j].UserID != place.UserID {
+			changed[place.UserID] = true
+		}
+	}
+	for j, place := range after[:min(n, len(after))] {
+		if j >= len(before) || before[j].UserID != place.UserID {
+			changed[place.UserID] = true
+		}
+	}
+	for userID := range changed {
+		err := firstStatsRef.Child(guildID).Child(channelID).Child("places").Child(userID).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+			var user *FirstPlaceStats
+			if err := value.Unmarshal(&user); err != nil {
+				return nil, err
+			}
+			stats := FirstStats{Places: map[string]*FirstPlaceStats{}}
+			if user != nil {
+				stats.Places[userID] = user
+			}
+			stats.addPlaces(before, n, -1)
+			stats.addPlaces(after, n, 1)
+			return stats.Places[userID], nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firstPlaceCounts lists how many times a user finished in each place
+func firstPlaceCounts(places []int) string {
+	var counts []string
+	for j, count := range places {
+		if count == 0 {
+			continue
+		}
+		if j < len(FIRST_PLACE_MEDALS) {
+			counts = append(counts, fmt.Sprintf("%s %d", FIRST_PLACE_MEDALS[j], count))
+		} else {
+			counts = append(counts, fmt.Sprintf("#%d %d", j + 1, count))
+		}
+	}
+	return strings.Join(counts, " ")
+}
+
+// handleFirstPodium follows up a deferred /first with the place leaderboard
+// of a game
+func handleFirstPodium(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string, place int) {
+	locale := interactionLocale(i)
+	var users map[string]*FirstPlaceStats
+	if err := firstStatsRef.Child(i.GuildID).Child(channelID).Child("places").Get(context.Background(), &users); err != nil {
+		log.Println("Error reading from database", err)
+		return
+	}
+	placeCount := func(user *FirstPlaceStats) int {
+		if place > len(user.Places) {
+			return 0
+		}
+		return user.Places[place - 1]
+	}
+	userIds := make([]string, 0, len(users))
+	for userId, user := range users {
+		if place == 0 || placeCount(user) > 0 {
+			userIds = append(userIds, userId)
+		}
+	}
+	sort.Slice(userIds, func(a, b int) bool {
+		userA, userB := users[userIds[a]], users[userIds[b]]
+		if place > 0 && placeCount(userA) != placeCount(userB) {
+			return placeCount(userA) > placeCount(userB)
+		}
+		if userA.Points != userB.Points {
+			return userA.Points > userB.Points
+		}
+		return userA.average() < userB.average()
+	})
+	var description string
+	for j, userId := range userIds[:min(15, len(userIds))] {
+		user := users[userId]
+		description += translate(locale, "first.podiumEntry", j + 1, userId, firstPlaceCounts(user.Places), user.Points, user.average())
+	}
+	if description == "" {
+		description = translate(locale, "first.podiumNone")
+	}
+	title := translate(locale, "first.podiumTitle")
+	if place > 0 {
+		title = translate(locale, "first.podiumPlaceTitle", place)
+	}
+	s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title: title,
+				Color: 0xff4d01,
+				Description: description,
+			},
+		},
+	})
+}
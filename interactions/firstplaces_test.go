@@ -0,0 +1,48 @@
+package interactions
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPlaceEntry(t *testing.T) {
+	place := func(userID string, date int64, of int) FirstPlace {
+		return FirstPlace{userID, strconv.FormatInt(date, 10), date, date - 100, of}
+	}
+	a, b, c := place("a", 101, 3), place("b", 102, 3), place("c", 103, 3)
+	tests := []struct {
+		name string
+		places []FirstPlace
+		n int
+		settled bool
+		entry FirstPlace
+		wantPlace int
+		want []FirstPlace
+	}{
+		{"first of the day", nil, 3, false, place("a", 101, 0), 1, []FirstPlace{a}},
+		{"after the others", []FirstPlace{a, b}, 3, false, place("c", 103, 0), 3, []FirstPlace{a, b, c}},
+		{"too late", []FirstPlace{a, b, c}, 3, false, place("d", 104, 0), 0, []FirstPlace{a, b, c}},
+		{"earlier message arriving late", []FirstPlace{a, b, c}, 3, false, place("d", 100, 0), 1, []FirstPlace{place("d", 100, 3), a, b}},
+		{"between others", []FirstPlace{a, c}, 3, false, place("b", 102, 0), 2, []FirstPlace{a, b, c}},
+		{"user already placed earlier", []FirstPlace{a, b}, 3, false, place("a", 103, 0), 0, []FirstPlace{a, b}},
+		{"user's earlier message arriving late", []FirstPlace{a, b}, 3, false, place("b", 100, 0), 1, []FirstPlace{place("b", 100, 3), a}},
+		{"user's earlier message in the same place", []FirstPlace{a, b, c}, 3, false, place("c", 102, 0), 3, []FirstPlace{a, b, place("c", 102, 3)}},
+		{"settled keeps places taken", []FirstPlace{a, c}, 3, true, place("b", 102, 0), 0, []FirstPlace{a, c}},
+		{"settled takes the next place", []FirstPlace{a, b}, 3, true, place("c", 103, 0), 3, []FirstPlace{a, b, c}},
+		{"settled keeps the user's place", []FirstPlace{a, b}, 3, true, place("b", 100, 0), 0, []FirstPlace{a, b}},
+		// The day keeps its own number of places when the game's changes
+		{"day with fewer places", []FirstPlace{place("a", 101, 2), place("b", 102, 2)}, 3, false, place("c", 103, 0), 0, []FirstPlace{place("a", 101, 2), place("b", 102, 2)}},
+		{"day with more places", []FirstPlace{place("a", 101, 4), place("b", 102, 4), place("c", 103, 4)}, 3, false, place("d", 104, 0), 4, []FirstPlace{place("a", 101, 4), place("b", 102, 4), place("c", 103, 4), place("d", 104, 4)}},
+	}
+	for _, test := range tests {
+		places := append([]FirstPlace(nil), test.places...)
+		gotPlace, got := placeEntry(places, test.n, test.settled, test.entry)
+		if gotPlace != test.wantPlace || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: placeEntry() = %d, %v, want %d, %v", test.name, gotPlace, got, test.wantPlace, test.want)
+		}
+		if !reflect.DeepEqual(places, test.places) {
+			t.Errorf("%s: placeEntry() changed its places to %v", test.name, places)
+		}
+	}
+}
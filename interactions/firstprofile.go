@@ -0,0 +1,156 @@
+package interactions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Number of weeks in the heatmap of a profile, ending with the current one
+const FIRST_HEATMAP_WEEKS = 12
+const FIRST_PROFILE_RECENT_WINS = 5
+
+type firstProfileOptions struct {
+	User *discordgo.User `option:"user" description:"User to show, you by default"`
+	Channel *discordgo.Channel `option:"channel" description:"Channel of the game, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+}
+
+// firstHeatmap draws the days a user won in the last FIRST_HEATMAP_WEEKS weeks
+// with a row for each day of the week, starting on Monday
+func firstHeatmap(won map[string]bool, today string) string {
+	todayDate, err := time.Parse(time.DateOnly, today)
+	if err != nil {
+		return ""
+	}
+	monday := todayDate.AddDate(0, 0, -(int(todayDate.Weekday()) + 6) % 7)
+	start := monday.AddDate(0, 0, -7 * (FIRST_HEATMAP_WEEKS - 1))
+	var rows []string
+	for weekday := 0; weekday < 7; weekday++ {
+		var row strings.Builder
+		for week := 0; week < FIRST_HEATMAP_WEEKS; week++ {
+			date := start.AddDate(0, 0, 7 * week + weekday)
+			if date.After(todayDate) {
+				break
+			}
+			if won[date.Format(time.DateOnly)] {
+				row.WriteString("🟩")
+			} else {
+				row.WriteString("⬛")
+			}
+		}
+		rows = append(rows, row.String())
+	}
+	return strings.Join(rows, "\n")
+}
+
+// handleFirstProfile follows up a deferred /first with a user's record in a
+// game, read from the game's aggregates and the days the user won
+func handleFirstProfile(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string, clock firstClock, user *discordgo.User) {
+	ctx := context.Background()
+	locale := interactionLocale(i)
+	statsRef := firstStatsRef.Child(i.GuildID).Child(channelID)
+	var stats *FirstUserStats
+	if err := statsRef.Child("users").Child(user.ID).Get(ctx, &stats); err != nil {
+		log.Println("Error reading from database", err)
+		return
+	}
+	var wins map[string]FirstWin
+	if err := statsRef.Child("wins").Child(user.ID).Get(ctx, &wins); err != nil {
+		log.Println("Error reading from database", err)
+		return
+	}
+	var latest *FirstLatest
+	if err := statsRef.Child("latest").Get(ctx, &latest); err != nil {
+		log.Println("Error reading from database", err)
+		return
+	}
+	var placeStats *FirstPlaceStats
+	if err := statsRef.Child("places").Child(user.ID).Get(ctx, &placeStats); err != nil {
+		log.Println("Error reading from database", err)
+		return
+	}
+	curTime, err := discordgo.SnowflakeTimestamp(i.ID)
+	if err != nil {
+		log.Println("Error getting interaction time", err)
+		return
+	}
+	today, _ := clock.day(curTime)
+
+	dates := make([]string, 0, len(wins))
+	times := make([]int64, 0, len(wins))
+	won := map[string]bool{}
+	var periodWins [len(TIME_PERIODS)]int
+	for date, win := range wins {
+		dates = append(dates, date)
+		times = append(times, win.Time)
+		won[date] = true
+		for j, timePeriod := range TIME_PERIODS {
+			if timePeriod.contains(date, today) {
+				periodWins[j]++
+			}
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: translate(locale, "first.profileTitle", user.Username),
+		Color: 0xff4d01,
+		Thumbnail: &discordgo.MessageEmbedThumbnail{URL: user.AvatarURL("")},
+	}
+	for j, timePeriod := range TIME_PERIODS {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: translate(locale, timePeriod.Name),
+			Value: fmt.Sprint(periodWins[j]),
+			Inline: true,
+		})
+	}
+	if latest != nil && latest.DayCount > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: translate(locale, "first.profileWinRate"),
+			Value: fmt.Sprintf("%.1f%% (%d/%d)", 100 * float64(len(dates)) / float64(latest.DayCount), len(dates), latest.DayCount),
+			Inline: true,
+		})
+	}
+	if placeStats != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: translate(locale, "first.profilePodium"),
+			Value: translate(locale, "first.profilePodiumValue", firstPlaceCounts(placeStats.Places), placeStats.Points, placeStats.average()),
+		})
+	}
+	if stats == nil || len(times) == 0 {
+		embed.Description = translate(locale, "first.profileNoWins", user.ID)
+	} else {
+		sort.Slice(times, func(a, b int) bool { return times[a] < times[b] })
+		median := times[len(times) / 2]
+		if len(times) % 2 == 0 {
+			median = (times[len(times) / 2 - 1] + median) / 2
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: translate(locale, "first.profileTimes"),
+			Value: translate(locale, "first.profileTimesValue", stats.Best, median, stats.average()),
+		})
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: translate(locale, "first.profileStreaks"),
+			Value: translate(locale, "first.profileStreaksValue", stats.currentStreak(today), stats.LongestStreak),
+		})
+		sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+		var recent string
+		for _, date := range dates[:min(FIRST_PROFILE_RECENT_WINS, len(dates))] {
+			recent += fmt.Sprintf("[%s](%s)\n", date, messageLink(i.GuildID, channelID, wins[date].MsgID))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: translate(locale, "first.profileRecent"),
+			Value: recent,
+		})
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name: translate(locale, "first.profileHeatmap", FIRST_HEATMAP_WEEKS),
+		Value: firstHeatmap(won, today),
+	})
+	s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	})
+}
@@ -0,0 +1,534 @@
+package interactions
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+	"firebase.google.com/go/v4/db"
+	"github.com/anishmit/gobot/firebase"
+)
+
+// Number of fastest first messages shown on the time leaderboard
+const FIRST_BEST_TIMES_KEPT = 15
+// Fastest first messages kept beyond those shown, so that the leaderboard
+// stays full when days are taken back
+const FIRST_BEST_TIMES_SPARE = 10
+// Version of the aggregates, raised when they gain fields so that ones built
+// before are rebuilt at startup
+const FIRST_STATS_VERSION = 4
+// Streaks at least this long get a notice when they end
+const FIRST_STREAK_NOTICE_MIN = 2
+// Times a rebuild starts over when first messages keep being recorded
+const FIRST_REBUILD_ATTEMPTS = 3
+
+// FirstStats are the aggregates of a game, stored under
+// firstStats/<guild>/<channel>. Each part is its own child so that a new
+// first message only touches the few it changes, in one update together with
+// its day under firstMessages. Leaderboards never read the raw days.
+type FirstStats struct {
+	Version int `json:"version"`
+	Latest *FirstLatest `json:"latest,omitempty"`
+	Users map[string]*FirstUserStats `json:"users,omitempty"`
+	// Winner of each day, for the leaderboards of recent days
+	Days map[string]string `json:"days,omitempty"`
+	// Days each user won keyed by user, then date
+	Wins map[string]map[string]FirstWin `json:"wins,omitempty"`
+	// Fastest first messages, fastest first
+	Best []FirstBestTime `json:"best,omitempty"`
+	// Places of each user across all days
+	Places map[string]*FirstPlaceStats `json:"places,omitempty"`
+}
+
+// FirstLatest is the latest day with a first message. The transaction that
+// decides each new first message runs on it alone, so it's kept small.
+type FirstLatest struct {
+	Date string `json:"date"`
+	// Nil if the day's first message was taken back
+	Message *FirstMessage `json:"message,omitempty"`
+	// Number of days with a first message
+	DayCount int `json:"dayCount"`
+}
+
+type FirstUserStats struct {
+	Count int `json:"count"`
+	// Fastest first message in milliseconds into its day
+	Best int64 `json:"best"`
+	BestDate string `json:"bestDate"`
+	// Sum of the times of all first messages, for the average
+	TotalTime int64 `json:"totalTime"`
+	// Consecutive days won up to LastDate
+	Streak int `json:"streak"`
+	LongestStreak int `json:"longestStreak"`
+	LastDate string `json:"lastDate"`
+}
+
+type FirstWin struct {
+	// Milliseconds into the day
+	Time int64 `json:"time"`
+	MsgID string `json:"msgId"`
+}
+
+// firstRecord is the outcome of recording a message
+type firstRecord struct {
+	// The message is the first of its day
+	Recorded bool
+	// The message is earlier than the first message already recorded for its
+	// day and took its place
+	Replaced bool
+	ReplacedMsgID string
+	// Streak that the message ended, if any
+	BrokenUserID string
+	BrokenStreak int
+}
+
+// average returns the average time of a user's first messages
+func (user *FirstUserStats) average() int64 {
+	if user.Count == 0 {
+		return 0
+	}
+	return user.TotalTime / int64(user.Count)
+}
+
+// currentStreak returns a user's streak if it's still going on a day, which
+// it is if they won that day or the one before
+func (user *FirstUserStats) currentStreak(today string) int {
+	if user.LastDate == today || user.LastDate == previousDate(today) {
+		return user.Streak
+	}
+	return 0
+}
+
+// addWin counts a day a user won, elapsed milliseconds into it. Days must be
+// added in order.
+func (user *FirstUserStats) addWin(date string, elapsed int64) {
+	user.Count++
+	if user.Count == 1 || elapsed < user.Best {
+		user.Best = elapsed
+		user.BestDate = date
+	}
+	user.TotalTime += elapsed
+	if user.LastDate == previousDate(date) {
+		user.Streak++
+	} else {
+		user.Streak = 1
+	}
+	user.LongestStreak = max(user.LongestStreak, user.Streak)
+	user.LastDate = date
+}
+
+// userStatsFromWins computes a user's stats from the days they won, or
+// returns nil if there are none
+func userStatsFromWins(wins map[string]FirstWin) *FirstUserStats {
+	if len(wins) == 0 {
+		return nil
+	}
+	dates := make([]string, 0, len(wins))
+	for date := range wins {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	user := &FirstUserStats{}
+	for _, date := range dates {
+		user.addWin(date, wins[date].Time)
+	}
+	return user
+}
+
+// shiftDate returns the date a number of days after another
+func shiftDate(date string, days int) string {
+	d, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return ""
+	}
+	return d.AddDate(0, 0, days).Format(time.DateOnly)
+}
+
+func previousDate(date string) string {
+	return shiftDate(date, -1)
+}
+
+type FirstBestTime struct {
+	Date string `json:"date"`
+	// Milliseconds into the day
+	Time int64 `json:"time"`
+	MsgID string `json:"msgId"`
+	UserID string `json:"userId"`
+}
+
+// insertBest adds a first message to the fastest ones if it's fast enough
+func insertBest(best []FirstBestTime, entry FirstBestTime) []FirstBestTime {
+	kept := FIRST_BEST_TIMES_KEPT + FIRST_BEST_TIMES_SPARE
+	index := sort.Search(len(best), func(j int) bool { return best[j].Time > entry.Time })
+	if index >= kept {
+		return best
+	}
+	best = append(best[:index:index], append([]FirstBestTime{entry}, best[index:]...)...)
+	return best[:min(len(best), kept)]
+}
+
+// removeBest takes the first message of a day out of the fastest ones
+func removeBest(best []FirstBestTime, date string) []FirstBestTime {
+	kept := []FirstBestTime{}
+	for _, entry := range best {
+		if entry.Date != date {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+var firstStatsRef = firebase.DB.NewRef("firstStats")
+var errFirstLatestChanged = errors.New("latest first message changed during rebuild")
+// Root of the database, for updates that span the days and the aggregates
+var firstRootRef = firebase.DB.NewRef("/")
+
+// firstStatsPath returns the path of a game's aggregates from the root
+func firstStatsPath(guildID, channelID string) string {
+	return "firstStats/" + guildID + "/" + channelID
+}
+
+// firstDayPath returns the path of a day's first message from the root
+func firstDayPath(guildID, channelID, date string) string {
+	return "firstMessages/" + guildID + "/" + channelID + "/" + date
+}
+
+// computeFirstStats computes the aggregates of a game from its days and the
+// places of each day, worth points for n places
+func computeFirstStats(days map[string]FirstMessage, places map[string][]FirstPlace, clock firstClock, n int) *FirstStats {
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	stats := &FirstStats{
+		Version: FIRST_STATS_VERSION,
+		Users: map[string]*FirstUserStats{},
+		Days: map[string]string{},
+		Wins: map[string]map[string]FirstWin{},
+	}
+	for _, date := range dates {
+		message := days[date]
+		elapsed, err := message.elapsed(clock, date)
+		if err != nil {
+			continue
+		}
+		user := stats.Users[message.UserID]
+		if user == nil {
+			user = &FirstUserStats{}
+			stats.Users[message.UserID] = user
+			stats.Wins[message.UserID] = map[string]FirstWin{}
+		}
+		user.addWin(date, elapsed)
+		stats.Days[date] = message.UserID
+		stats.Wins[message.UserID][date] = FirstWin{elapsed, message.MsgID}
+		stats.Best = insertBest(stats.Best, FirstBestTime{date, elapsed, message.MsgID, message.UserID})
+		stats.Latest = &FirstLatest{date, &message, len(stats.Days)}
+	}
+	for _, dayPlaces := range places {
+		stats.addPlaces(dayPlaces, n, 1)
+	}
+	return stats
+}
+
+// recordFirstMessage decides whether a message is the first of its day and
+// if so saves the day along with the aggregates it changes in one update. A
+// small transaction on the latest day decides, ignoring messages for a day
+// before it since that day is over. An earlier message that arrives late only
+// replaces the latest day's first message if canReplace. Callers hold the
+// channel's lock, so the aggregates read after the transaction can't change
+// before the update.
+func recordFirstMessage(ctx context.Context, guildID, channelID, date string, message FirstMessage, canReplace bool) (firstRecord, error) {
+	latestRef := firstStatsRef.Child(guildID).Child(channelID).Child("latest")
+	var record firstRecord
+	var previous *FirstLatest
+	err := latestRef.Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		record = firstRecord{}
+		previous = nil
+		if err := value.Unmarshal(&previous); err != nil {
+			return nil, err
+		}
+		switch {
+		case previous == nil || date > previous.Date || (date == previous.Date && previous.Message == nil):
+			latest := &FirstLatest{date, &message, 1}
+			if previous != nil {
+				latest.DayCount = previous.DayCount + 1
+			}
+			record.Recorded = true
+			return latest, nil
+		case date == previous.Date && canReplace && message.Date < previous.Message.Date:
+			record.Recorded, record.Replaced, record.ReplacedMsgID = true, true, previous.Message.MsgID
+			return &FirstLatest{date, &message, previous.DayCount}, nil
+		}
+		return previous, nil
+	})
+	if err != nil || !record.Recorded {
+		return record, err
+	}
+	var updates map[string]interface{}
+	if record.Replaced {
+		updates, err = firstWinnerUpdates(ctx, guildID, channelID, date, previous.Message, &message)
+	} else {
+		updates, record.BrokenUserID, record.BrokenStreak, err = newFirstUpdates(ctx, guildID, channelID, date, previous, message)
+	}
+	if err == nil {
+		err = firstRootRef.Update(ctx, updates)
+	}
+	if err != nil {
+		// Otherwise the latest day would claim a first message that isn't saved
+		restoreFirstLatest(ctx, latestRef, date, message.MsgID, previous)
+		return firstRecord{}, err
+	}
+	return record, nil
+}
+
+// newFirstUpdates returns the writes that add a new latest day to a game and
+// the streak that the day ended, if any
+func newFirstUpdates(ctx context.Context, guildID, channelID, date string, previous *FirstLatest, message FirstMessage) (map[string]interface{}, string, int, error) {
+	statsRef := firstStatsRef.Child(guildID).Child(channelID)
+	var user *FirstUserStats
+	if err := statsRef.Child("users").Child(message.UserID).Get(ctx, &user); err != nil {
+		return nil, "", 0, err
+	}
+	if user == nil {
+		user = &FirstUserStats{}
+	}
+	brokenUserID, brokenStreak := "", 0
+	if previous != nil && previous.Message != nil && (previous.Message.UserID != message.UserID || previous.Date != previousDate(date)) {
+		previousUser := user
+		if previous.Message.UserID != message.UserID {
+			previousUser = nil
+			if err := statsRef.Child("users").Child(previous.Message.UserID).Get(ctx, &previousUser); err != nil {
+				return nil, "", 0, err
+			}
+		}
+		if previousUser != nil && previousUser.LastDate == previous.Date {
+			brokenUserID, brokenStreak = previous.Message.UserID, previousUser.Streak
+		}
+	}
+	user.addWin(date, message.Time)
+	var best []FirstBestTime
+	if err := statsRef.Child("best").Get(ctx, &best); err != nil {
+		return nil, "", 0, err
+	}
+	path := firstStatsPath(guildID, channelID)
+	updates := map[string]interface{}{
+		firstDayPath(guildID, channelID, date): message,
+		path + "/users/" + message.UserID: user,
+		path + "/days/" + date: message.UserID,
+		path + "/wins/" + message.UserID + "/" + date: FirstWin{message.Time, message.MsgID},
+		path + "/best": insertBest(best, FirstBestTime{date, message.Time, message.MsgID, message.UserID}),
+	}
+	if previous == nil {
+		updates[path + "/version"] = FIRST_STATS_VERSION
+	}
+	return updates, brokenUserID, brokenStreak, nil
+}
+
+// firstWinnerUpdates returns the writes that change the first message of a
+// day from old to message, either of which can be nil for none. The day can
+// be any day, so the users' stats are recomputed from the days they won.
+func firstWinnerUpdates(ctx context.Context, guildID, channelID, date string, old, message *FirstMessage) (map[string]interface{}, error) {
+	statsRef := firstStatsRef.Child(guildID).Child(channelID)
+	wins := map[string]map[string]FirstWin{}
+	for _, m := range []*FirstMessage{old, message} {
+		if m == nil || wins[m.UserID] != nil {
+			continue
+		}
+		var userWins map[string]FirstWin
+		if err := statsRef.Child("wins").Child(m.UserID).Get(ctx, &userWins); err != nil {
+			return nil, err
+		}
+		if userWins == nil {
+			userWins = map[string]FirstWin{}
+		}
+		wins[m.UserID] = userWins
+	}
+	var best []FirstBestTime
+	if err := statsRef.Child("best").Get(ctx, &best); err != nil {
+		return nil, err
+	}
+	best = removeBest(best, date)
+	path := firstStatsPath(guildID, channelID)
+	updates := map[string]interface{}{}
+	if old != nil {
+		delete(wins[old.UserID], date)
+		updates[path + "/wins/" + old.UserID + "/" + date] = nil
+		updates[path + "/days/" + date] = nil
+		updates[firstDayPath(guildID, channelID, date)] = nil
+	}
+	if message != nil {
+		win := FirstWin{message.Time, message.MsgID}
+		wins[message.UserID][date] = win
+		updates[path + "/wins/" + message.UserID + "/" + date] = win
+		updates[path + "/days/" + date] = message.UserID
+		updates[firstDayPath(guildID, channelID, date)] = message
+		best = insertBest(best, FirstBestTime{date, message.Time, message.MsgID, message.UserID})
+	}
+	for userID, userWins := range wins {
+		updates[path + "/users/" + userID] = userStatsFromWins(userWins)
+	}
+	updates[path + "/best"] = best
+	return updates, nil
+}
+
+// changeFirstWinner changes the first message of any day from old to message,
+// either of which can be nil for none, in one update along with extra writes
+// from the root. The latest day is then brought in line.
+func changeFirstWinner(ctx context.Context, guildID, channelID, date string, old, message *FirstMessage, extra map[string]interface{}) error {
+	updates, err := firstWinnerUpdates(ctx, guildID, channelID, date, old, message)
+	if err != nil {
+		return err
+	}
+	for path, value := range extra {
+		updates[path] = value
+	}
+	if err := firstRootRef.Update(ctx, updates); err != nil {
+		return err
+	}
+	return firstStatsRef.Child(guildID).Child(channelID).Child("latest").Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var latest *FirstLatest
+		if err := value.Unmarshal(&latest); err != nil {
+			return nil, err
+		}
+		if latest == nil {
+			if message == nil {
+				return latest, nil
+			}
+			return &FirstLatest{date, message, 1}, nil
+		}
+		if old == nil && message != nil {
+			latest.DayCount++
+		} else if old != nil && message == nil {
+			latest.DayCount--
+		}
+		if date == latest.Date || (date > latest.Date && message != nil) {
+			latest.Date, latest.Message = date, message
+		}
+		return latest, nil
+	})
+}
+
+// restoreFirstLatest undoes a latest day whose update failed, unless another
+// message changed it since
+func restoreFirstLatest(ctx context.Context, latestRef *db.Ref, date, msgID string, previous *FirstLatest) {
+	err := latestRef.Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var latest *FirstLatest
+		if err := value.Unmarshal(&latest); err != nil {
+			return nil, err
+		}
+		if latest == nil || latest.Date != date || latest.Message == nil || latest.Message.MsgID != msgID {
+			return latest, nil
+		}
+		return previous, nil
+	})
+	if err != nil {
+		log.Println("Error restoring latest first message", err)
+	}
+}
+
+// rebuildFirstStats recomputes the aggregates of a game from its days and
+// places and returns the number of days. It starts over if a first message
+// is recorded meanwhile by a process that doesn't hold the channel's lock.
+func rebuildFirstStats(ctx context.Context, guildID, channelID string) (int, error) {
+	defer lockFirstChannel(channelID)()
+	for attempt := 1; ; attempt++ {
+		days, err := rebuildFirstStatsOnce(ctx, guildID, channelID)
+		if !errors.Is(err, errFirstLatestChanged) || attempt == FIRST_REBUILD_ATTEMPTS {
+			return days, err
+		}
+	}
+}
+
+// rebuildFirstStatsOnce writes the aggregates computed from the days and
+// places read, and then the latest day through its transaction, which fails
+// if a first message was recorded since the days were read
+func rebuildFirstStatsOnce(ctx context.Context, guildID, channelID string) (int, error) {
+	statsRef := firstStatsRef.Child(guildID).Child(channelID)
+	var seen *FirstLatest
+	if err := statsRef.Child("latest").Get(ctx, &seen); err != nil {
+		return 0, err
+	}
+	var days map[string]FirstMessage
+	if err := firstMessagesRef.Child(guildID).Child(channelID).Get(ctx, &days); err != nil {
+		return 0, err
+	}
+	var places map[string][]FirstPlace
+	if err := firstPlacesRef.Child(guildID).Child(channelID).Get(ctx, &places); err != nil {
+		return 0, err
+	}
+	// This runs at startup, before guild configs are cached
+	config, err := loadGuildConfig(ctx, guildID)
+	if err != nil {
+		return 0, err
+	}
+	game := config.FirstGames[channelID]
+	stats := computeFirstStats(days, places, game.clock(config), game.places())
+	err = statsRef.Update(ctx, map[string]interface{}{
+		"version": stats.Version,
+		"users": stats.Users,
+		"days": stats.Days,
+		"wins": stats.Wins,
+		"best": stats.Best,
+		"places": stats.Places,
+	})
+	if err != nil {
+		return 0, err
+	}
+	err = statsRef.Child("latest").Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var latest *FirstLatest
+		if err := value.Unmarshal(&latest); err != nil {
+			return nil, err
+		}
+		if !sameFirstLatest(latest, seen) {
+			return nil, errFirstLatestChanged
+		}
+		return stats.Latest, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(days), nil
+}
+
+// sameFirstLatest reports whether two latest days have the same first message
+func sameFirstLatest(a, b *FirstLatest) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Date != b.Date || a.DayCount != b.DayCount || (a.Message == nil) != (b.Message == nil) {
+		return false
+	}
+	return a.Message == nil || a.Message.MsgID == b.Message.MsgID
+}
+
+// rebuildOutdatedFirstStats computes the aggregates of games that have days
+// but no aggregates or ones from an older FIRST_STATS_VERSION
+func rebuildOutdatedFirstStats(ctx context.Context) error {
+	var guildIDs map[string]interface{}
+	if err := firstMessagesRef.GetShallow(ctx, &guildIDs); err != nil {
+		return err
+	}
+	for guildID := range guildIDs {
+		var channelIDs map[string]interface{}
+		if err := firstMessagesRef.Child(guildID).GetShallow(ctx, &channelIDs); err != nil {
+			return err
+		}
+		for channelID := range channelIDs {
+			var version int
+			if err := firstStatsRef.Child(guildID).Child(channelID).Child("version").Get(ctx, &version); err != nil {
+				return err
+			}
+			if version == FIRST_STATS_VERSION {
+				continue
+			}
+			days, err := rebuildFirstStats(ctx, guildID, channelID)
+			if err != nil {
+				return err
+			}
+			log.Println("Built first message stats for", guildID, channelID, "from", days, "days")
+		}
+	}
+	return nil
+}
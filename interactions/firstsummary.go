@@ -0,0 +1,153 @@
+package interactions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Reaction added to the first message of each day
+const FIRST_WINNER_REACTION = "🥇"
+// Minutes after the start of a day that its summary is posted by default.
+// Messages from before then that arrive late can still take first place.
+const FIRST_DEFAULT_SUMMARY_DELAY = 5
+
+type firstAnnounceOptions struct {
+	Reactions bool `option:"reactions" description:"React to the first message of each day" required:"true"`
+	Summary bool `option:"summary" description:"Post a summary of each day once its window closes" required:"true"`
+	SummaryDelay int `option:"summary_delay" description:"Minutes after the day starts that the window closes" default:"5" min:"1" max:"1439"`
+	Channel *discordgo.Channel `option:"channel" description:"Channel of the game, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+}
+
+// scheduleFirstSummary schedules a job that posts the summaries of a game with
+// its current settings if summaries are on, and sets the game's SummaryJobID
+// to it. The job it replaces is left for saveFirstGame to cancel.
+func scheduleFirstSummary(ctx context.Context, i *discordgo.InteractionCreate, channelID string, game *FirstGame) error {
+	game.SummaryJobID = ""
+	if !game.Summary {
+		return nil
+	}
+	createdTime, err := discordgo.SnowflakeTimestamp(i.ID)
+	if err != nil {
+		return err
+	}
+	minutes := (game.DayStart + game.SummaryDelay) % (24 * 60)
+	recurrence := &Recurrence{
+		Cron: fmt.Sprintf("0 %d %d * * *", minutes % 60, minutes / 60),
+		Timezone: game.clock(getGuildConfig(i.GuildID)).loc.String(),
+	}
+	next, ok := recurrence.next(createdTime)
+	if !ok {
+		return fmt.Errorf("summary schedule %q never fires", recurrence.Cron)
+	}
+	job := &ScheduledJob{
+		Kind: "firstSummary",
+		GuildID: i.GuildID,
+		ChannelID: channelID,
+		UserID: invokingUser(i).ID,
+		Time: next.UnixMilli(),
+		MissedPolicy: MISSED_LATE,
+		CreatedAt: createdTime.UnixMilli(),
+		Locale: interactionLocale(i),
+		Recurrence: recurrence,
+	}
+	if err := scheduleJob(ctx, job); err != nil {
+		return err
+	}
+	game.SummaryJobID = job.ID
+	return nil
+}
+
+// saveFirstGame saves a game, or removes it if game is nil, and then cancels
+// the summary job it had before. If saving fails the game's new job is
+// cancelled instead, so that the saved game and its job always match.
+func saveFirstGame(ctx context.Context, guildID, channelID string, game *FirstGame, oldJobID string) error {
+	newJobID := ""
+	if game != nil {
+		newJobID = game.SummaryJobID
+	}
+	if err := setFirstGame(ctx, guildID, channelID, game); err != nil {
+		if newJobID != "" && newJobID != oldJobID {
+			if _, err := cancelJob(ctx, newJobID); err != nil {
+				log.Println("Error cancelling first message summary", err)
+			}
+		}
+		return err
+	}
+	if oldJobID != "" && oldJobID != newJobID {
+		// A job that can't be cancelled now ends on its own when it fires
+		// and finds that it isn't the game's
+		if _, err := cancelJob(ctx, oldJobID); err != nil {
+			log.Println("Error cancelling first message summary", err)
+		}
+	}
+	return nil
+}
+
+// summaryPosted reports whether the summary of the day that starts at start
+// has been posted by now, after which the day's places are settled
+func (game FirstGame) summaryPosted(start, now time.Time) bool {
+	return game.Summary && !now.Before(start.Add(time.Duration(game.SummaryDelay) * time.Minute))
+}
+
+// prepareFirstSummary returns the function that posts the summary of the day
+// a summary job fires in
+func prepareFirstSummary(s *discordgo.Session, job *ScheduledJob) (func() (*discordgo.Message, error), error) {
+	ctx := context.Background()
+	config, err := loadGuildConfig(ctx, job.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	game, ok := config.FirstGames[job.ChannelID]
+	if !ok || !game.Summary || game.SummaryJobID != job.ID {
+		return nil, errJobObsolete
+	}
+	jobTime := time.UnixMilli(job.Time)
+	clock := game.clock(config)
+	date, _ := clock.day(jobTime)
+	locale := guildLocale(s, job.GuildID)
+
+	var winner *FirstMessage
+	if err := firstMessagesRef.Child(job.GuildID).Child(job.ChannelID).Child(date).Get(ctx, &winner); err != nil {
+		return nil, err
+	}
+	content := translate(locale, "first.summaryNone", date)
+	if winner != nil {
+		elapsed, err := winner.elapsed(clock, date)
+		if err != nil {
+			return nil, err
+		}
+		content = translate(locale, "first.summaryHeader", date) + "\n" +
+			translate(locale, "first.summaryWinner", winner.UserID, elapsed, messageLink(job.GuildID, job.ChannelID, winner.MsgID))
+		var user *FirstUserStats
+		if err := firstStatsRef.Child(job.GuildID).Child(job.ChannelID).Child("users").Child(winner.UserID).Get(ctx, &user); err != nil {
+			return nil, err
+		}
+		if user != nil {
+			content += "\n" + translate(locale, "first.summaryStreak", user.currentStreak(date), user.LongestStreak)
+		}
+		var places []FirstPlace
+		if err := firstPlacesRef.Child(job.GuildID).Child(job.ChannelID).Child(date).Get(ctx, &places); err != nil {
+			return nil, err
+		}
+		for j, place := range places {
+			if place.UserID == winner.UserID {
+				continue
+			}
+			content += "\n" + translate(locale, "first.summaryRunnerUp", j + 1, place.UserID, place.Time, messageLink(job.GuildID, job.ChannelID, place.MsgID))
+		}
+	}
+	send := &discordgo.MessageSend{
+		Content: content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	}
+	return func() (*discordgo.Message, error) {
+		return s.ChannelMessageSendComplex(job.ChannelID, send)
+	}, nil
+}
+
+func init() {
+	jobRunners["firstSummary"] = prepareFirstSummary
+}
@@ -0,0 +1,138 @@
+package interactions
+
+import (
+	"context"
+	"log"
+	"sync"
+	"firebase.google.com/go/v4/db"
+	"github.com/bwmarrin/discordgo"
+	"github.com/anishmit/gobot/firebase"
+)
+
+type GuildConfig struct {
+	Timezone string `json:"timezone"`
+	// First message games keyed by channel
+	FirstGames map[string]FirstGame `json:"firstGames,omitempty"`
+}
+
+var DEFAULT_GUILD_CONFIG = GuildConfig{
+	Timezone: "UTC",
+}
+var guildConfigs = map[string]GuildConfig{}
+var guildConfigsMutex sync.RWMutex
+
+// getGuildConfig returns the cached config of a guild, or the defaults if it
+// hasn't been loaded
+func getGuildConfig(guildID string) GuildConfig {
+	guildConfigsMutex.RLock()
+	defer guildConfigsMutex.RUnlock()
+	if config, ok := guildConfigs[guildID]; ok {
+		return config
+	}
+	return DEFAULT_GUILD_CONFIG
+}
+
+// withDefaults fills unset fields of a guild config with their defaults
+func (config GuildConfig) withDefaults() GuildConfig {
+	if config.Timezone == "" {
+		config.Timezone = DEFAULT_GUILD_CONFIG.Timezone
+	}
+	return config
+}
+
+var guildConfigsRef = firebase.DB.NewRef("guildConfigs")
+
+// loadGuildConfig reads the config of a guild from the database, for code that
+// can run before the guild's config is cached
+func loadGuildConfig(ctx context.Context, guildID string) (GuildConfig, error) {
+	var config GuildConfig
+	if err := guildConfigsRef.Child(guildID).Get(ctx, &config); err != nil {
+		return config, err
+	}
+	return config.withDefaults(), nil
+}
+
+// setGuildTimezone saves the default timezone of a guild
+func setGuildTimezone(ctx context.Context, guildID, timezone string) error {
+	if err := guildConfigsRef.Child(guildID).Child("timezone").Set(ctx, timezone); err != nil {
+		return err
+	}
+	guildConfigsMutex.Lock()
+	config, ok := guildConfigs[guildID]
+	if !ok {
+		config = DEFAULT_GUILD_CONFIG
+	}
+	config.Timezone = timezone
+	guildConfigs[guildID] = config
+	guildConfigsMutex.Unlock()
+	return nil
+}
+
+// setFirstGame saves the first message game of a channel, or removes it if
+// game is nil
+func setFirstGame(ctx context.Context, guildID, channelID string, game *FirstGame) error {
+	ref := guildConfigsRef.Child(guildID).Child("firstGames").Child(channelID)
+	var err error
+	if game == nil {
+		err = ref.Delete(ctx)
+	} else {
+		err = ref.Set(ctx, game)
+	}
+	if err != nil {
+		return err
+	}
+	guildConfigsMutex.Lock()
+	defer guildConfigsMutex.Unlock()
+	config, ok := guildConfigs[guildID]
+	if !ok {
+		config = DEFAULT_GUILD_CONFIG
+	}
+	// The map is copied since configs handed out by getGuildConfig share it
+	games := map[string]FirstGame{}
+	for id, existing := range config.FirstGames {
+		games[id] = existing
+	}
+	if game == nil {
+		delete(games, channelID)
+	} else {
+		games[channelID] = *game
+	}
+	config.FirstGames = games
+	guildConfigs[guildID] = config
+	return nil
+}
+
+func init() {
+	ctx := context.Background()
+
+	// Guild create is sent for every guild on connect as well as when the bot
+	// joins a guild, so only fields that are missing get seeded
+	GuildCreateHandlers = append(GuildCreateHandlers, func(s *discordgo.Session, g *discordgo.GuildCreate) {
+		var config GuildConfig
+		err := guildConfigsRef.Child(g.ID).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+			var existing GuildConfig
+			if err := value.Unmarshal(&existing); err != nil {
+				return nil, err
+			}
+			config = existing.withDefaults()
+			return config, nil
+		})
+		if err != nil {
+			log.Println("Error seeding guild config", err)
+			return
+		}
+		guildConfigsMutex.Lock()
+		guildConfigs[g.ID] = config
+		guildConfigsMutex.Unlock()
+	})
+
+	GuildDeleteHandlers = append(GuildDeleteHandlers, func(s *discordgo.Session, g *discordgo.GuildDelete) {
+		// Unavailable guilds are outages, not removals
+		if g.Unavailable {
+			return
+		}
+		guildConfigsMutex.Lock()
+		delete(guildConfigs, g.ID)
+		guildConfigsMutex.Unlock()
+	})
+}
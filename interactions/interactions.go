@@ -1,11 +1,86 @@
 package interactions
 
 import (
+	"context"
+	"time"
 	"github.com/bwmarrin/discordgo"
 )
 
 var Commands []*discordgo.ApplicationCommand
 var CommandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){}
+// Autocomplete handlers are keyed by command name
+var AutocompleteHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){}
+// Component and modal handlers are keyed by the part of the custom ID before
+// the first ":", and the rest of it can carry arguments
 var ComponentHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){}
+var ModalHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){}
 var MessageCreateHandlers []func(s *discordgo.Session, m *discordgo.MessageCreate)
-var ReadyHandlers []func(s *discordgo.Session, r *discordgo.Ready)
\ No newline at end of file
+var MessageUpdateHandlers []func(s *discordgo.Session, m *discordgo.MessageUpdate)
+var MessageDeleteHandlers []func(s *discordgo.Session, m *discordgo.MessageDelete)
+var ReadyHandlers []func(s *discordgo.Session, r *discordgo.Ready) error
+var DisconnectHandlers []func(s *discordgo.Session, d *discordgo.Disconnect)
+var ResumedHandlers []func(s *discordgo.Session, r *discordgo.Resumed)
+var GuildCreateHandlers []func(s *discordgo.Session, g *discordgo.GuildCreate)
+var GuildDeleteHandlers []func(s *discordgo.Session, g *discordgo.GuildDelete)
+var VoiceStateUpdateHandlers []func(s *discordgo.Session, v *discordgo.VoiceStateUpdate)
+
+// Tasks every process runs before opening its sessions, for changes to stored
+// data that have to be in place before anything caches it. They must be safe
+// to run from several processes at once.
+var StartupTasks []func(ctx context.Context) error
+
+// Jobs that only one process may run at a time, keyed by leader lock name.
+// A job runs while its lock is held and must return once ctx is cancelled.
+var LeaderJobs = map[string]func(ctx context.Context, s *discordgo.Session){}
+
+// Ready handlers are retried with exponential backoff since they run on
+// every reconnect and shouldn't take the bot down on a transient error
+const READY_ATTEMPTS = 5
+const READY_RETRY_DELAY = 2 * time.Second
+
+// Retry calls fn until it succeeds or has been called attempts times,
+// doubling delay after each failure
+func Retry(attempts int, delay time.Duration, fn func() error) error {
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt < attempts - 1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return err
+}
+
+// Where commands can be installed and used
+var (
+	GUILD_INTEGRATIONS = &[]discordgo.ApplicationIntegrationType{discordgo.ApplicationIntegrationGuildInstall}
+	ALL_INTEGRATIONS = &[]discordgo.ApplicationIntegrationType{discordgo.ApplicationIntegrationGuildInstall, discordgo.ApplicationIntegrationUserInstall}
+	GUILD_CONTEXTS = &[]discordgo.InteractionContextType{discordgo.InteractionContextGuild}
+	ALL_CONTEXTS = &[]discordgo.InteractionContextType{discordgo.InteractionContextGuild, discordgo.InteractionContextBotDM, discordgo.InteractionContextPrivateChannel}
+)
+
+// invokingUser returns the user who triggered an interaction, which is set on
+// i.Member in guilds and on i.User in DMs and group DMs
+func invokingUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+// modalValue returns the value of a text input in a submitted modal
+func modalValue(data discordgo.ModalSubmitInteractionData, customID string) string {
+	for _, row := range data.Components {
+		if row, ok := row.(*discordgo.ActionsRow); ok {
+			for _, component := range row.Components {
+				if input, ok := component.(*discordgo.TextInput); ok && input.CustomID == customID {
+					return input.Value
+				}
+			}
+		}
+	}
+	return ""
+}
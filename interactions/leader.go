@@ -0,0 +1,128 @@
+package interactions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+	"firebase.google.com/go/v4/db"
+	"github.com/bwmarrin/discordgo"
+	"github.com/anishmit/gobot/firebase"
+)
+
+// A leader lock expires unless its holder renews it, so a crashed process
+// hands its jobs over to another one after at most LEADER_LOCK_TTL
+const LEADER_LOCK_TTL = 30 * time.Second
+const LEADER_LOCK_RENEW_INTERVAL = 10 * time.Second
+
+type LeaderLock struct {
+	Holder string `json:"holder"`
+	Expires int64 `json:"expires"`
+}
+
+var INSTANCE_ID = func() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+}()
+var leaderLocksRef = firebase.DB.NewRef("leaderLocks")
+var heldLeaderLocks = map[string]bool{}
+var heldLeaderLocksMutex sync.Mutex
+var leaderJobsOnce sync.Once
+
+// acquireLeaderLock takes or renews a lock, reporting whether this process
+// holds it afterwards
+func acquireLeaderLock(ctx context.Context, name string) (bool, error) {
+	held := false
+	err := leaderLocksRef.Child(name).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var lock LeaderLock
+		if err := value.Unmarshal(&lock); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		if lock.Holder != "" && lock.Holder != INSTANCE_ID && lock.Expires > now.UnixMilli() {
+			held = false
+			return lock, nil
+		}
+		held = true
+		return LeaderLock{
+			Holder: INSTANCE_ID,
+			Expires: now.Add(LEADER_LOCK_TTL).UnixMilli(),
+		}, nil
+	})
+	if err != nil {
+		held = false
+	}
+	heldLeaderLocksMutex.Lock()
+	heldLeaderLocks[name] = held
+	heldLeaderLocksMutex.Unlock()
+	return held, err
+}
+
+// releaseLeaderLock gives up a lock if this process holds it
+func releaseLeaderLock(ctx context.Context, name string) error {
+	return leaderLocksRef.Child(name).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		var lock LeaderLock
+		if err := value.Unmarshal(&lock); err != nil {
+			return nil, err
+		}
+		if lock.Holder != INSTANCE_ID {
+			return lock, nil
+		}
+		return nil, nil
+	})
+}
+
+// ReleaseLeaderLocks gives up every lock this process holds so another process
+// can take over its jobs without waiting for them to expire
+func ReleaseLeaderLocks() {
+	heldLeaderLocksMutex.Lock()
+	defer heldLeaderLocksMutex.Unlock()
+	for name, held := range heldLeaderLocks {
+		if held {
+			if err := releaseLeaderLock(context.Background(), name); err != nil {
+				log.Println("Error releasing leader lock", name, err)
+			}
+		}
+	}
+}
+
+// runAsLeader keeps trying to hold a lock, running job while it is held and
+// cancelling it when the lock is lost
+func runAsLeader(name string, s *discordgo.Session, job func(ctx context.Context, s *discordgo.Session)) {
+	ctx := context.Background()
+	var cancel context.CancelFunc
+	ticker := time.NewTicker(LEADER_LOCK_RENEW_INTERVAL)
+	defer ticker.Stop()
+	for {
+		held, err := acquireLeaderLock(ctx, name)
+		if err != nil {
+			log.Println("Error acquiring leader lock", name, err)
+		}
+		if held && cancel == nil {
+			log.Println("Acquired leader lock", name)
+			var jobCtx context.Context
+			jobCtx, cancel = context.WithCancel(ctx)
+			go job(jobCtx, s)
+		} else if !held && cancel != nil {
+			log.Println("Lost leader lock", name)
+			cancel()
+			cancel = nil
+		}
+		<-ticker.C
+	}
+}
+
+func init() {
+	// REST calls work from any shard's session, so jobs start once with the
+	// first session that becomes ready
+	ReadyHandlers = append(ReadyHandlers, func(s *discordgo.Session, r *discordgo.Ready) error {
+		leaderJobsOnce.Do(func() {
+			for name, job := range LeaderJobs {
+				go runAsLeader(name, s, job)
+			}
+		})
+		return nil
+	})
+}
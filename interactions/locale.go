@@ -0,0 +1,1402 @@
+package interactions
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+)
+
+const DEFAULT_LOCALE = discordgo.EnglishUS
+
+// Locales that share translations with another locale
+var LOCALE_FALLBACKS = map[discordgo.Locale]discordgo.Locale{
+	discordgo.SpanishLATAM: discordgo.SpanishES,
+	discordgo.EnglishGB: discordgo.EnglishUS,
+}
+
+// Message catalog keyed by message ID. Keys starting with "command." hold
+// translations of command metadata: "command.<command>[.<option>...]" is
+// the description of a chat command or option, and the name of a context
+// menu command. English command metadata lives in the command definitions.
+var messages = map[string]map[discordgo.Locale]string{
+	// Commands
+	"command.ask": {
+		discordgo.SpanishES: "Pregúntale algo al bot",
+		discordgo.Hindi: "बॉट से कुछ पूछें",
+	},
+	"command.ask.prompt": {
+		discordgo.SpanishES: "Pregunta para el bot",
+		discordgo.Hindi: "बॉट के लिए प्रॉम्प्ट",
+	},
+	"command.first": {
+		discordgo.SpanishES: "Datos sobre los primeros mensajes",
+		discordgo.Hindi: "पहले संदेशों के बारे में जानकारी",
+	},
+	"command.first.count": {
+		discordgo.SpanishES: "Clasificación por número de primeros mensajes",
+		discordgo.Hindi: "पहले संदेशों की संख्या का लीडरबोर्ड",
+	},
+	"command.first.time": {
+		discordgo.SpanishES: "Clasificación de los primeros mensajes más rápidos",
+		discordgo.Hindi: "सबसे तेज़ पहले संदेशों का लीडरबोर्ड",
+	},
+	"command.first.count.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.time.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.streaks": {
+		discordgo.SpanishES: "Clasificación de rachas de días ganados seguidos, con mejores tiempos y promedios",
+		discordgo.Hindi: "लगातार जीते गए दिनों का लीडरबोर्ड, सबसे अच्छे और औसत समय के साथ",
+	},
+	"command.first.streaks.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.profile": {
+		discordgo.SpanishES: "Historial de primeros mensajes de un usuario",
+		discordgo.Hindi: "किसी उपयोगकर्ता का पहले संदेशों का रिकॉर्ड",
+	},
+	"command.first.profile.user": {
+		discordgo.SpanishES: "Usuario a mostrar, tú por defecto",
+		discordgo.Hindi: "दिखाया जाने वाला उपयोगकर्ता, डिफ़ॉल्ट रूप से आप",
+	},
+	"command.first.profile.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.podium": {
+		discordgo.SpanishES: "Clasificación de puestos, con puntos y puesto promedio",
+		discordgo.Hindi: "स्थानों का लीडरबोर्ड, अंकों और औसत स्थान के साथ",
+	},
+	"command.first.podium.place": {
+		discordgo.SpanishES: "Ordenar por cuántas veces los usuarios quedaron en este puesto en lugar de por puntos",
+		discordgo.Hindi: "अंकों के बजाय इस बात से क्रमबद्ध करें कि उपयोगकर्ता कितनी बार इस स्थान पर रहे",
+	},
+	"command.first.podium.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.announce": {
+		discordgo.SpanishES: "Elige cómo se anuncia al ganador de cada día",
+		discordgo.Hindi: "चुनें कि हर दिन के विजेता की घोषणा कैसे हो",
+	},
+	"command.first.announce.reactions": {
+		discordgo.SpanishES: "Reaccionar al primer mensaje de cada día",
+		discordgo.Hindi: "हर दिन के पहले संदेश पर प्रतिक्रिया दें",
+	},
+	"command.first.announce.summary": {
+		discordgo.SpanishES: "Publicar un resumen de cada día cuando cierre su ventana",
+		discordgo.Hindi: "हर दिन की विंडो बंद होने पर उसका सारांश पोस्ट करें",
+	},
+	"command.first.announce.summary_delay": {
+		discordgo.SpanishES: "Minutos después del inicio del día en que se cierra la ventana",
+		discordgo.Hindi: "दिन शुरू होने के कितने मिनट बाद विंडो बंद होती है",
+	},
+	"command.first.announce.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.enable": {
+		discordgo.SpanishES: "Empieza el juego de primer mensaje en un canal o cambia su configuración",
+		discordgo.Hindi: "किसी चैनल में फ़र्स्ट मैसेज गेम शुरू करें या उसकी सेटिंग बदलें",
+	},
+	"command.first.enable.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.enable.timezone": {
+		discordgo.SpanishES: "Zona horaria IANA en la que se cuentan los días, la del servidor por defecto",
+		discordgo.Hindi: "IANA टाइमज़ोन जिसमें दिन गिने जाते हैं, डिफ़ॉल्ट रूप से सर्वर का",
+	},
+	"command.first.enable.day_start": {
+		discordgo.SpanishES: "Hora a la que empiezan los días, como 04:00, medianoche por defecto",
+		discordgo.Hindi: "दिन शुरू होने का समय, जैसे 04:00, डिफ़ॉल्ट रूप से आधी रात",
+	},
+	"command.first.enable.places": {
+		discordgo.SpanishES: "Número de usuarios que obtienen puesto cada día, 3 por defecto",
+		discordgo.Hindi: "हर दिन स्थान पाने वाले उपयोगकर्ताओं की संख्या, डिफ़ॉल्ट रूप से 3",
+	},
+	"command.first.edits": {
+		discordgo.SpanishES: "Elige qué pasa con los mensajes con puesto que se editan",
+		discordgo.Hindi: "चुनें कि स्थान पाने वाले संपादित संदेशों का क्या होगा",
+	},
+	"command.first.edits.policy": {
+		discordgo.SpanishES: "Qué pasa con un mensaje con puesto que se edita",
+		discordgo.Hindi: "स्थान पाने वाला संदेश संपादित होने पर क्या होगा",
+	},
+	"command.first.edits.window": {
+		discordgo.SpanishES: "Solo cuentan las ediciones hasta estos segundos después del mensaje, cualquiera si es 0",
+		discordgo.Hindi: "संदेश के इतने सेकंड के भीतर के संपादन ही गिने जाएँगे, 0 होने पर कोई भी",
+	},
+	"command.first.edits.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.void": {
+		discordgo.SpanishES: "Quita un día de los registros de un juego",
+		discordgo.Hindi: "किसी दिन को गेम के रिकॉर्ड से हटाएँ",
+	},
+	"command.first.void.date": {
+		discordgo.SpanishES: "Día a anular, como 2024-01-31",
+		discordgo.Hindi: "रद्द करने का दिन, जैसे 2024-01-31",
+	},
+	"command.first.void.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.unvoid": {
+		discordgo.SpanishES: "Vuelve a contar un día anulado",
+		discordgo.Hindi: "रद्द किए गए दिन को फिर से गिनें",
+	},
+	"command.first.unvoid.date": {
+		discordgo.SpanishES: "Día anulado que vuelve a contar, como 2024-01-31",
+		discordgo.Hindi: "रद्द किया गया दिन जिसे फिर से गिनना है, जैसे 2024-01-31",
+	},
+	"command.first.unvoid.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.disable": {
+		discordgo.SpanishES: "Detiene el juego de primer mensaje en un canal, conservando sus datos",
+		discordgo.Hindi: "किसी चैनल में फ़र्स्ट मैसेज गेम बंद करें, उसका डेटा रखते हुए",
+	},
+	"command.first.rebuild": {
+		discordgo.SpanishES: "Recalcula las clasificaciones de un juego a partir de sus primeros mensajes",
+		discordgo.Hindi: "किसी गेम के लीडरबोर्ड उसके पहले संदेशों से फिर से गिनें",
+	},
+	"command.first.rebuild.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.first.disable.channel": {
+		discordgo.SpanishES: "Canal del juego, este por defecto",
+		discordgo.Hindi: "गेम का चैनल, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.imagen": {
+		discordgo.SpanishES: "Genera una imagen con Imagen 3",
+		discordgo.Hindi: "Imagen 3 से एक चित्र बनाएँ",
+	},
+	"command.imagen.prompt": {
+		discordgo.SpanishES: "Instrucción para la imagen generada",
+		discordgo.Hindi: "बनाए जाने वाले चित्र का प्रॉम्प्ट",
+	},
+	"command.imagen.aspect_ratio": {
+		discordgo.SpanishES: "Relación de aspecto de la imagen generada",
+		discordgo.Hindi: "बनाए जाने वाले चित्र का आस्पेक्ट रेशियो",
+	},
+	"command.remind": {
+		discordgo.SpanishES: "Te recuerda algo más tarde",
+		discordgo.Hindi: "बाद में आपको किसी चीज़ की याद दिलाएँ",
+	},
+	"command.remind.here": {
+		discordgo.SpanishES: "Mencionarte en este canal en lugar de enviar un mensaje directo",
+		discordgo.Hindi: "DM भेजने के बजाय इस चैनल में आपको पिंग करें",
+	},
+	"command.remind.text": {
+		discordgo.SpanishES: "De qué recordarte",
+		discordgo.Hindi: "किस बारे में याद दिलाना है",
+	},
+	"command.remind.when": {
+		discordgo.SpanishES: "Cuándo recordártelo, como in 2h30m, tomorrow 9am o fri 17:00",
+		discordgo.Hindi: "कब याद दिलाना है, जैसे in 2h30m, tomorrow 9am या fri 17:00",
+	},
+	"command.Remind me about this": {
+		discordgo.SpanishES: "Recuérdamelo",
+		discordgo.Hindi: "मुझे इसकी याद दिलाएँ",
+	},
+	"command.send": {
+		discordgo.SpanishES: "Programa y gestiona mensajes",
+		discordgo.Hindi: "संदेश शेड्यूल और प्रबंधित करें",
+	},
+	"command.send.cancel": {
+		discordgo.SpanishES: "Cancela un mensaje programado",
+		discordgo.Hindi: "शेड्यूल किया गया संदेश रद्द करें",
+	},
+	"command.send.cancel.id": {
+		discordgo.SpanishES: "ID del mensaje programado",
+		discordgo.Hindi: "शेड्यूल किए गए संदेश की ID",
+	},
+	"command.send.edit": {
+		discordgo.SpanishES: "Cambia la hora o el contenido de un mensaje programado",
+		discordgo.Hindi: "शेड्यूल किए गए संदेश का समय या सामग्री बदलें",
+	},
+	"command.send.edit.content": {
+		discordgo.SpanishES: "Nuevo contenido del mensaje",
+		discordgo.Hindi: "संदेश की नई सामग्री",
+	},
+	"command.send.edit.id": {
+		discordgo.SpanishES: "ID del mensaje programado",
+		discordgo.Hindi: "शेड्यूल किए गए संदेश की ID",
+	},
+	"command.send.edit.time": {
+		discordgo.SpanishES: "Nueva hora, como in 2h30m, tomorrow 9am, fri 17:00 o tiempo Unix en ms",
+		discordgo.Hindi: "नया समय, जैसे in 2h30m, tomorrow 9am, fri 17:00 या ms में यूनिक्स समय",
+	},
+	"command.send.list": {
+		discordgo.SpanishES: "Lista los mensajes programados",
+		discordgo.Hindi: "शेड्यूल किए गए संदेशों की सूची",
+	},
+	"command.send.list.all": {
+		discordgo.SpanishES: "Lista los mensajes programados de todos en este servidor, para administradores",
+		discordgo.Hindi: "इस सर्वर में सभी के शेड्यूल किए गए संदेश दिखाएँ, सर्वर मैनेजरों के लिए",
+	},
+	"command.send.list.page": {
+		discordgo.SpanishES: "Página que mostrar",
+		discordgo.Hindi: "दिखाने के लिए पेज",
+	},
+	"command.send.pause": {
+		discordgo.SpanishES: "Pausa un mensaje programado",
+		discordgo.Hindi: "शेड्यूल किए गए संदेश को रोकें",
+	},
+	"command.send.pause.id": {
+		discordgo.SpanishES: "ID del mensaje programado",
+		discordgo.Hindi: "शेड्यूल किए गए संदेश की ID",
+	},
+	"command.send.resume": {
+		discordgo.SpanishES: "Reanuda un mensaje programado en pausa",
+		discordgo.Hindi: "रोके गए शेड्यूल किए गए संदेश को फिर से शुरू करें",
+	},
+	"command.send.resume.id": {
+		discordgo.SpanishES: "ID del mensaje programado",
+		discordgo.Hindi: "शेड्यूल किए गए संदेश की ID",
+	},
+	"command.send.schedule": {
+		discordgo.SpanishES: "Programa el envío de un mensaje",
+		discordgo.Hindi: "संदेश भेजना शेड्यूल करें",
+	},
+	"command.send.schedule.channel": {
+		discordgo.SpanishES: "Canal, hilo o publicación del foro donde enviarlo, este por defecto",
+		discordgo.Hindi: "भेजने के लिए चैनल, थ्रेड या फ़ोरम पोस्ट, डिफ़ॉल्ट रूप से यही",
+	},
+	"command.send.schedule.color": {
+		discordgo.SpanishES: "Color del embed en hexadecimal, como #ff8000",
+		discordgo.Hindi: "एम्बेड का रंग हेक्स कोड में, जैसे #ff8000",
+	},
+	"command.send.schedule.count": {
+		discordgo.SpanishES: "Deja de repetir después de esta cantidad de mensajes",
+		discordgo.Hindi: "इतने संदेशों के बाद दोहराना बंद करें",
+	},
+	"command.send.schedule.content": {
+		discordgo.SpanishES: "Contenido del mensaje, se pide en un formulario si no se da nada más",
+		discordgo.Hindi: "संदेश की सामग्री, कुछ और न देने पर फ़ॉर्म में पूछी जाएगी",
+	},
+	"command.send.schedule.description": {
+		discordgo.SpanishES: "Descripción del embed",
+		discordgo.Hindi: "एम्बेड का विवरण",
+	},
+	"command.send.schedule.dm": {
+		discordgo.SpanishES: "Enviártelo por mensaje directo",
+		discordgo.Hindi: "इसे आपको DM में भेजें",
+	},
+	"command.send.schedule.file": {
+		discordgo.SpanishES: "Archivo adjunto",
+		discordgo.Hindi: "संलग्न करने के लिए फ़ाइल",
+	},
+	"command.send.schedule.mentions": {
+		discordgo.SpanishES: "Menciones que pueden notificar",
+		discordgo.Hindi: "कौन से मेंशन पिंग कर सकते हैं",
+	},
+	"command.send.schedule.missed": {
+		discordgo.SpanishES: "Qué hacer si el bot estaba desconectado a esa hora",
+		discordgo.Hindi: "उस समय बॉट ऑफ़लाइन हो तो क्या करें",
+	},
+	"command.send.schedule.repeat": {
+		discordgo.SpanishES: "Repetir como daily 09:00, weekly mon,fri 17:00 o una expresión cron",
+		discordgo.Hindi: "दोहराएँ, जैसे daily 09:00, weekly mon,fri 17:00 या cron एक्सप्रेशन",
+	},
+	"command.send.schedule.time": {
+		discordgo.SpanishES: "Cuándo enviarlo, como in 2h30m, tomorrow 9am, fri 17:00 o tiempo Unix en ms",
+		discordgo.Hindi: "कब भेजना है, जैसे in 2h30m, tomorrow 9am, fri 17:00 या ms में यूनिक्स समय",
+	},
+	"command.send.schedule.title": {
+		discordgo.SpanishES: "Título del embed",
+		discordgo.Hindi: "एम्बेड का शीर्षक",
+	},
+	"command.send.schedule.until": {
+		discordgo.SpanishES: "Deja de repetir después de esta hora",
+		discordgo.Hindi: "इस समय के बाद दोहराना बंद करें",
+	},
+	"command.snowflake": {
+		discordgo.SpanishES: "Decodifica, codifica y compara IDs de Discord",
+		discordgo.Hindi: "Discord ID को डिकोड, एनकोड और तुलना करें",
+	},
+	"command.snowflake.decode": {
+		discordgo.SpanishES: "Muestra de qué está hecho un ID de Discord",
+		discordgo.Hindi: "दिखाएँ कि Discord ID किससे बनी है",
+	},
+	"command.snowflake.decode.id": {
+		discordgo.SpanishES: "ID de Discord",
+		discordgo.Hindi: "Discord ID",
+	},
+	"command.snowflake.diff": {
+		discordgo.SpanishES: "Muestra cuántos milisegundos separan dos IDs de Discord",
+		discordgo.Hindi: "दिखाएँ कि दो Discord ID कितने मिलीसेकंड के अंतर पर हैं",
+	},
+	"command.snowflake.diff.a": {
+		discordgo.SpanishES: "Primer ID de Discord",
+		discordgo.Hindi: "पहली Discord ID",
+	},
+	"command.snowflake.diff.b": {
+		discordgo.SpanishES: "Segundo ID de Discord",
+		discordgo.Hindi: "दूसरी Discord ID",
+	},
+	"command.snowflake.encode": {
+		discordgo.SpanishES: "Obtén los IDs mínimo y máximo de una hora, para búsquedas con before y after",
+		discordgo.Hindi: "किसी समय की सबसे छोटी और बड़ी ID पाएँ, before और after खोज के लिए",
+	},
+	"command.snowflake.encode.time": {
+		discordgo.SpanishES: "Hora, como 2026-12-31 23:59:59.500, in 2h o tiempo Unix en ms",
+		discordgo.Hindi: "समय, जैसे 2026-12-31 23:59:59.500, in 2h या ms में यूनिक्स समय",
+	},
+	"command.timezone": {
+		discordgo.SpanishES: "Configura la zona horaria en la que se leen y muestran las horas",
+		discordgo.Hindi: "वह टाइमज़ोन सेट करें जिसमें समय पढ़े और दिखाए जाते हैं",
+	},
+	"command.timezone.set": {
+		discordgo.SpanishES: "Configura tu zona horaria, usada para las horas que das y las que te muestra el bot",
+		discordgo.Hindi: "अपना टाइमज़ोन सेट करें, जो आपके दिए समय और बॉट के दिखाए समय के लिए इस्तेमाल होता है",
+	},
+	"command.timezone.set.zone": {
+		discordgo.SpanishES: "Zona horaria IANA, como America/New_York o Asia/Kolkata",
+		discordgo.Hindi: "IANA टाइमज़ोन, जैसे America/New_York या Asia/Kolkata",
+	},
+	"command.timezone.clear": {
+		discordgo.SpanishES: "Volver a la zona horaria predeterminada del servidor",
+		discordgo.Hindi: "सर्वर के डिफ़ॉल्ट टाइमज़ोन पर वापस जाएँ",
+	},
+	"command.timezone.server": {
+		discordgo.SpanishES: "Configura la zona horaria predeterminada del servidor",
+		discordgo.Hindi: "सर्वर का डिफ़ॉल्ट टाइमज़ोन सेट करें",
+	},
+	"command.timezone.server.zone": {
+		discordgo.SpanishES: "Zona horaria IANA, como America/New_York o Asia/Kolkata",
+		discordgo.Hindi: "IANA टाइमज़ोन, जैसे America/New_York या Asia/Kolkata",
+	},
+	"command.timezone.show": {
+		discordgo.SpanishES: "Muestra tu zona horaria y la predeterminada del servidor",
+		discordgo.Hindi: "अपना टाइमज़ोन और सर्वर का डिफ़ॉल्ट दिखाएँ",
+	},
+	"command.Timestamp": {
+		discordgo.SpanishES: "Marca de tiempo",
+		discordgo.Hindi: "टाइमस्टैम्प",
+	},
+	"command.User info": {
+		discordgo.SpanishES: "Información del usuario",
+		discordgo.Hindi: "उपयोगकर्ता जानकारी",
+	},
+	"command.ud": {
+		discordgo.SpanishES: "Buscar en Urban Dictionary",
+		discordgo.Hindi: "Urban Dictionary में खोजें",
+	},
+	"command.ud.term": {
+		discordgo.SpanishES: "Término a buscar",
+		discordgo.Hindi: "खोजने के लिए शब्द",
+	},
+	"command.yt": {
+		discordgo.SpanishES: "Reproducir un video de YouTube",
+		discordgo.Hindi: "YouTube वीडियो चलाएँ",
+	},
+	"command.yt.query": {
+		discordgo.SpanishES: "Búsqueda",
+		discordgo.Hindi: "खोज",
+	},
+
+	// Options
+	"option.missing": {
+		discordgo.EnglishUS: "Missing required option `%s`.",
+		discordgo.SpanishES: "Falta la opción obligatoria `%s`.",
+		discordgo.Hindi: "ज़रूरी विकल्प `%s` नहीं दिया गया।",
+	},
+	"option.requires": {
+		discordgo.EnglishUS: "Option `%s` only works together with `%s`.",
+		discordgo.SpanishES: "La opción `%s` solo funciona junto con `%s`.",
+		discordgo.Hindi: "विकल्प `%s` केवल `%s` के साथ काम करता है।",
+	},
+	"option.type": {
+		discordgo.EnglishUS: "Option `%s` should be %s, not %s.",
+		discordgo.SpanishES: "La opción `%s` debe ser %s, no %s.",
+		discordgo.Hindi: "विकल्प `%s` %s होना चाहिए, %s नहीं।",
+	},
+	"option.min": {
+		discordgo.EnglishUS: "Option `%s` must be at least %s.",
+		discordgo.SpanishES: "La opción `%s` debe ser al menos %s.",
+		discordgo.Hindi: "विकल्प `%s` कम से कम %s होना चाहिए।",
+	},
+	"option.max": {
+		discordgo.EnglishUS: "Option `%s` must be at most %s.",
+		discordgo.SpanishES: "La opción `%s` debe ser como máximo %s.",
+		discordgo.Hindi: "विकल्प `%s` अधिक से अधिक %s होना चाहिए।",
+	},
+	"option.minLength": {
+		discordgo.EnglishUS: "Option `%s` must be at least %s characters long.",
+		discordgo.SpanishES: "La opción `%s` debe tener al menos %s caracteres.",
+		discordgo.Hindi: "विकल्प `%s` में कम से कम %s अक्षर होने चाहिए।",
+	},
+	"option.maxLength": {
+		discordgo.EnglishUS: "Option `%s` must be at most %s characters long.",
+		discordgo.SpanishES: "La opción `%s` debe tener como máximo %s caracteres.",
+		discordgo.Hindi: "विकल्प `%s` में अधिक से अधिक %s अक्षर होने चाहिए।",
+	},
+	"option.choices": {
+		discordgo.EnglishUS: "Option `%s` must be one of %s.",
+		discordgo.SpanishES: "La opción `%s` debe ser una de %s.",
+		discordgo.Hindi: "विकल्प `%s` इनमें से एक होना चाहिए: %s।",
+	},
+	"option.notMember": {
+		discordgo.EnglishUS: "<@%s> is not a member of this server.",
+		discordgo.SpanishES: "<@%s> no es miembro de este servidor.",
+		discordgo.Hindi: "<@%s> इस सर्वर का सदस्य नहीं है।",
+	},
+	"option.attachment": {
+		discordgo.EnglishUS: "Attachment `%s` could not be found.",
+		discordgo.SpanishES: "No se encontró el archivo adjunto `%s`.",
+		discordgo.Hindi: "अटैचमेंट `%s` नहीं मिला।",
+	},
+
+	// First
+	"first.today": {
+		discordgo.EnglishUS: "Today",
+		discordgo.SpanishES: "Hoy",
+		discordgo.Hindi: "आज",
+	},
+	"first.pastWeek": {
+		discordgo.EnglishUS: "Past Week",
+		discordgo.SpanishES: "Última semana",
+		discordgo.Hindi: "पिछला सप्ताह",
+	},
+	"first.pastMonth": {
+		discordgo.EnglishUS: "Past Month",
+		discordgo.SpanishES: "Último mes",
+		discordgo.Hindi: "पिछला महीना",
+	},
+	"first.pastYear": {
+		discordgo.EnglishUS: "Past Year",
+		discordgo.SpanishES: "Último año",
+		discordgo.Hindi: "पिछला साल",
+	},
+	"first.allTime": {
+		discordgo.EnglishUS: "All Time",
+		discordgo.SpanishES: "Todo el tiempo",
+		discordgo.Hindi: "अब तक",
+	},
+	"first.countTitle": {
+		discordgo.EnglishUS: "First Leaderboard (Count)",
+		discordgo.SpanishES: "Clasificación de primeros (cantidad)",
+		discordgo.Hindi: "फ़र्स्ट लीडरबोर्ड (संख्या)",
+	},
+	"first.timeTitle": {
+		discordgo.EnglishUS: "First Leaderboard (Time)",
+		discordgo.SpanishES: "Clasificación de primeros (tiempo)",
+		discordgo.Hindi: "फ़र्स्ट लीडरबोर्ड (समय)",
+	},
+	"first.dayStart": {
+		discordgo.EnglishUS: "Days start at %s in %s, which is %s in %s",
+		discordgo.SpanishES: "Los días empiezan a las %s en %s, que son las %s en %s",
+		discordgo.Hindi: "दिन %[2]s में %[1]s बजे शुरू होते हैं, जो %[4]s में %[3]s है",
+	},
+	"first.noGame": {
+		discordgo.EnglishUS: "There's no first message game in <#%s>. Server managers can start one with `/first enable`.",
+		discordgo.SpanishES: "No hay juego de primer mensaje en <#%s>. Los administradores del servidor pueden empezar uno con `/first enable`.",
+		discordgo.Hindi: "<#%s> में कोई फ़र्स्ट मैसेज गेम नहीं है। सर्वर मैनेजर `/first enable` से एक शुरू कर सकते हैं।",
+	},
+	"first.notManager": {
+		discordgo.EnglishUS: "Only server managers can change first message games.",
+		discordgo.SpanishES: "Solo los administradores del servidor pueden cambiar los juegos de primer mensaje.",
+		discordgo.Hindi: "सिर्फ़ सर्वर मैनेजर ही फ़र्स्ट मैसेज गेम बदल सकते हैं।",
+	},
+	"first.invalidDayStart": {
+		discordgo.EnglishUS: "Couldn't understand the day start `%s`. Use a time like `00:00` or `4am`.",
+		discordgo.SpanishES: "No se entendió el inicio del día `%s`. Usa una hora como `00:00` o `4am`.",
+		discordgo.Hindi: "दिन की शुरुआत `%s` समझ नहीं आई। `00:00` या `4am` जैसा समय इस्तेमाल करें।",
+	},
+	"first.saveFailed": {
+		discordgo.EnglishUS: "Couldn't save the game. Try again later.",
+		discordgo.SpanishES: "No se pudo guardar el juego. Inténtalo más tarde.",
+		discordgo.Hindi: "गेम सेव नहीं हो सका। बाद में फिर कोशिश करें।",
+	},
+	"first.enabled": {
+		discordgo.EnglishUS: "The first message game is on in <#%s>, with days starting at %02[3]d:%02[4]d in %[2]s and %[5]d places each day.",
+		discordgo.SpanishES: "El juego de primer mensaje está activo en <#%s>, con días que empiezan a las %02[3]d:%02[4]d en %[2]s y %[5]d puestos cada día.",
+		discordgo.Hindi: "<#%s> में फ़र्स्ट मैसेज गेम चालू है, जिसमें दिन %[2]s में %02[3]d:%02[4]d बजे शुरू होते हैं और हर दिन %[5]d स्थान हैं।",
+	},
+	"first.disabled": {
+		discordgo.EnglishUS: "The first message game in <#%s> is off. Its data is kept in case it's turned back on.",
+		discordgo.SpanishES: "El juego de primer mensaje en <#%s> está desactivado. Sus datos se guardan por si se vuelve a activar.",
+		discordgo.Hindi: "<#%s> में फ़र्स्ट मैसेज गेम बंद है। इसका डेटा रखा गया है, ताकि इसे फिर से चालू किया जा सके।",
+	},
+	"first.rebuilt": {
+		discordgo.EnglishUS: "Rebuilt the leaderboards of <#%s> from %d days.",
+		discordgo.SpanishES: "Se reconstruyeron las clasificaciones de <#%s> a partir de %d días.",
+		discordgo.Hindi: "<#%s> के लीडरबोर्ड %d दिनों से फिर से बनाए गए।",
+	},
+	"first.rebuildFailed": {
+		discordgo.EnglishUS: "Couldn't rebuild the leaderboards. Try again later.",
+		discordgo.SpanishES: "No se pudieron reconstruir las clasificaciones. Inténtalo más tarde.",
+		discordgo.Hindi: "लीडरबोर्ड फिर से नहीं बन सके। बाद में फिर कोशिश करें।",
+	},
+	"first.streaksTitle": {
+		discordgo.EnglishUS: "First Leaderboard (Streaks)",
+		discordgo.SpanishES: "Clasificación de primeros (rachas)",
+		discordgo.Hindi: "फ़र्स्ट लीडरबोर्ड (लगातार जीत)",
+	},
+	"first.streakEntry": {
+		discordgo.EnglishUS: "%d. <@%s>: **%d** in a row (longest %d), best `%d` ms, average `%d` ms\n",
+		discordgo.SpanishES: "%d. <@%s>: **%d** seguidos (máximo %d), mejor `%d` ms, promedio `%d` ms\n",
+		discordgo.Hindi: "%d. <@%s>: लगातार **%d** (सबसे लंबी %d), सबसे अच्छा `%d` ms, औसत `%d` ms\n",
+	},
+	"first.streakBroken": {
+		discordgo.EnglishUS: "<@%s> ended <@%s>'s streak of %d days!",
+		discordgo.SpanishES: "¡<@%s> terminó la racha de %[3]d días de <@%[2]s>!",
+		discordgo.Hindi: "<@%s> ने <@%s> की लगातार %d दिनों की जीत रोक दी!",
+	},
+	"first.streakEnded": {
+		discordgo.EnglishUS: "<@%s>'s streak of %d days ended.",
+		discordgo.SpanishES: "La racha de %[2]d días de <@%[1]s> terminó.",
+		discordgo.Hindi: "<@%s> की लगातार %d दिनों की जीत खत्म हो गई।",
+	},
+	"first.profileTitle": {
+		discordgo.EnglishUS: "First Profile: %s",
+		discordgo.SpanishES: "Perfil de primeros: %s",
+		discordgo.Hindi: "फ़र्स्ट प्रोफ़ाइल: %s",
+	},
+	"first.profileNoWins": {
+		discordgo.EnglishUS: "<@%s> hasn't sent a first message here yet.",
+		discordgo.SpanishES: "<@%s> todavía no ha enviado un primer mensaje aquí.",
+		discordgo.Hindi: "<@%s> ने अभी तक यहाँ कोई पहला संदेश नहीं भेजा है।",
+	},
+	"first.profileWinRate": {
+		discordgo.EnglishUS: "Win Rate",
+		discordgo.SpanishES: "Porcentaje de victorias",
+		discordgo.Hindi: "जीत दर",
+	},
+	"first.profileTimes": {
+		discordgo.EnglishUS: "Times",
+		discordgo.SpanishES: "Tiempos",
+		discordgo.Hindi: "समय",
+	},
+	"first.profileTimesValue": {
+		discordgo.EnglishUS: "Fastest `%d` ms, median `%d` ms, average `%d` ms",
+		discordgo.SpanishES: "Más rápido `%d` ms, mediana `%d` ms, promedio `%d` ms",
+		discordgo.Hindi: "सबसे तेज़ `%d` ms, माध्यिका `%d` ms, औसत `%d` ms",
+	},
+	"first.profileStreaks": {
+		discordgo.EnglishUS: "Streaks",
+		discordgo.SpanishES: "Rachas",
+		discordgo.Hindi: "लगातार जीत",
+	},
+	"first.profileStreaksValue": {
+		discordgo.EnglishUS: "Current %d, longest %d",
+		discordgo.SpanishES: "Actual %d, máxima %d",
+		discordgo.Hindi: "मौजूदा %d, सबसे लंबी %d",
+	},
+	"first.profileRecent": {
+		discordgo.EnglishUS: "Recent Wins",
+		discordgo.SpanishES: "Victorias recientes",
+		discordgo.Hindi: "हाल की जीत",
+	},
+	"first.profileHeatmap": {
+		discordgo.EnglishUS: "Last %d Weeks",
+		discordgo.SpanishES: "Últimas %d semanas",
+		discordgo.Hindi: "पिछले %d सप्ताह",
+	},
+	"first.announceBoth": {
+		discordgo.EnglishUS: "The first message of each day in <#%s> gets a reaction, and a summary is posted %d minutes after the day starts.",
+		discordgo.SpanishES: "El primer mensaje de cada día en <#%s> recibe una reacción, y se publica un resumen %d minutos después de que empiece el día.",
+		discordgo.Hindi: "<#%s> में हर दिन के पहले संदेश पर प्रतिक्रिया दी जाती है, और दिन शुरू होने के %d मिनट बाद सारांश पोस्ट किया जाता है।",
+	},
+	"first.announceReactions": {
+		discordgo.EnglishUS: "The first message of each day in <#%s> gets a reaction, without a summary.",
+		discordgo.SpanishES: "El primer mensaje de cada día en <#%s> recibe una reacción, sin resumen.",
+		discordgo.Hindi: "<#%s> में हर दिन के पहले संदेश पर प्रतिक्रिया दी जाती है, सारांश के बिना।",
+	},
+	"first.announceSummary": {
+		discordgo.EnglishUS: "A summary of each day in <#%s> is posted %d minutes after the day starts, without reactions.",
+		discordgo.SpanishES: "Se publica un resumen de cada día en <#%s> %d minutos después de que empiece el día, sin reacciones.",
+		discordgo.Hindi: "<#%s> में हर दिन का सारांश दिन शुरू होने के %d मिनट बाद पोस्ट किया जाता है, प्रतिक्रियाओं के बिना।",
+	},
+	"first.announceNone": {
+		discordgo.EnglishUS: "Winners in <#%s> aren't announced.",
+		discordgo.SpanishES: "Los ganadores en <#%s> no se anuncian.",
+		discordgo.Hindi: "<#%s> में विजेताओं की घोषणा नहीं की जाती।",
+	},
+	"first.summaryNone": {
+		discordgo.EnglishUS: "Nobody has sent a message on %s yet.",
+		discordgo.SpanishES: "Nadie ha enviado un mensaje el %s todavía.",
+		discordgo.Hindi: "%s को अभी तक किसी ने संदेश नहीं भेजा है।",
+	},
+	"first.summaryHeader": {
+		discordgo.EnglishUS: "**First message of %s**",
+		discordgo.SpanishES: "**Primer mensaje del %s**",
+		discordgo.Hindi: "**%s का पहला संदेश**",
+	},
+	"first.summaryWinner": {
+		discordgo.EnglishUS: "🥇 <@%s> at **%d** ms ([message](%s))",
+		discordgo.SpanishES: "🥇 <@%s> a los **%d** ms ([mensaje](%s))",
+		discordgo.Hindi: "🥇 <@%s>, **%d** ms पर ([संदेश](%s))",
+	},
+	"first.summaryStreak": {
+		discordgo.EnglishUS: "🔥 %d in a row, longest %d",
+		discordgo.SpanishES: "🔥 %d seguidos, máximo %d",
+		discordgo.Hindi: "🔥 लगातार %d, सबसे लंबी %d",
+	},
+	"first.summaryRunnerUp": {
+		discordgo.EnglishUS: "%d. <@%s> at %d ms ([message](%s))",
+		discordgo.SpanishES: "%d. <@%s> a los %d ms ([mensaje](%s))",
+		discordgo.Hindi: "%d. <@%s>, %d ms पर ([संदेश](%s))",
+	},
+	"first.podiumTitle": {
+		discordgo.EnglishUS: "Podium",
+		discordgo.SpanishES: "Podio",
+		discordgo.Hindi: "पोडियम",
+	},
+	"first.podiumPlaceTitle": {
+		discordgo.EnglishUS: "Most #%d finishes",
+		discordgo.SpanishES: "Más veces en el puesto %d",
+		discordgo.Hindi: "सबसे ज़्यादा बार स्थान %d",
+	},
+	"first.podiumEntry": {
+		discordgo.EnglishUS: "%d. <@%s>: %s · %d points · average place %.2f\n",
+		discordgo.SpanishES: "%d. <@%s>: %s · %d puntos · puesto promedio %.2f\n",
+		discordgo.Hindi: "%d. <@%s>: %s · %d अंक · औसत स्थान %.2f\n",
+	},
+	"first.podiumNone": {
+		discordgo.EnglishUS: "Nobody has placed yet.",
+		discordgo.SpanishES: "Nadie ha obtenido puesto todavía.",
+		discordgo.Hindi: "अभी तक किसी ने स्थान नहीं पाया है।",
+	},
+	"first.profilePodium": {
+		discordgo.EnglishUS: "Places",
+		discordgo.SpanishES: "Puestos",
+		discordgo.Hindi: "स्थान",
+	},
+	"first.profilePodiumValue": {
+		discordgo.EnglishUS: "%s\n%d points, average place %.2f",
+		discordgo.SpanishES: "%s\n%d puntos, puesto promedio %.2f",
+		discordgo.Hindi: "%s\n%d अंक, औसत स्थान %.2f",
+	},
+	"first.edits.flag": {
+		discordgo.EnglishUS: "flagged",
+		discordgo.SpanishES: "se señalan",
+		discordgo.Hindi: "चिह्नित किए जाते हैं",
+	},
+	"first.edits.disqualify": {
+		discordgo.EnglishUS: "disqualified",
+		discordgo.SpanishES: "se descalifican",
+		discordgo.Hindi: "अयोग्य ठहराए जाते हैं",
+	},
+	"first.editsIgnore": {
+		discordgo.EnglishUS: "Edits to placed messages in <#%s> are ignored.",
+		discordgo.SpanishES: "Las ediciones de mensajes con puesto en <#%s> se ignoran.",
+		discordgo.Hindi: "<#%s> में स्थान पाने वाले संदेशों के संपादन अनदेखे किए जाते हैं।",
+	},
+	"first.editsAny": {
+		discordgo.EnglishUS: "Placed messages in <#%s> that are edited are %s.",
+		discordgo.SpanishES: "Los mensajes con puesto en <#%s> que se editan %s.",
+		discordgo.Hindi: "<#%s> में स्थान पाने वाले संपादित संदेश %s।",
+	},
+	"first.editsWindow": {
+		discordgo.EnglishUS: "Placed messages in <#%s> that are edited within %d seconds are %s.",
+		discordgo.SpanishES: "Los mensajes con puesto en <#%s> que se editan en menos de %d segundos %s.",
+		discordgo.Hindi: "<#%s> में %d सेकंड के भीतर संपादित होने वाले स्थान पाने वाले संदेश %s।",
+	},
+	"first.editFlagged": {
+		discordgo.EnglishUS: "<@%s> edited their place %d message of %s %d seconds after sending it.",
+		discordgo.SpanishES: "<@%s> editó su mensaje del puesto %d del %s %d segundos después de enviarlo.",
+		discordgo.Hindi: "<@%s> ने %[3]s का अपना स्थान %[2]d वाला संदेश भेजने के %[4]d सेकंड बाद संपादित किया।",
+	},
+	"first.editDisqualified": {
+		discordgo.EnglishUS: "<@%s> edited their place %d message of %s %d seconds after sending it, so it's disqualified.",
+		discordgo.SpanishES: "<@%s> editó su mensaje del puesto %d del %s %d segundos después de enviarlo, así que queda descalificado.",
+		discordgo.Hindi: "<@%s> ने %[3]s का अपना स्थान %[2]d वाला संदेश भेजने के %[4]d सेकंड बाद संपादित किया, इसलिए वह अयोग्य है।",
+	},
+	"first.invalidDate": {
+		discordgo.EnglishUS: "`%s` isn't a date like 2024-01-31.",
+		discordgo.SpanishES: "`%s` no es una fecha como 2024-01-31.",
+		discordgo.Hindi: "`%s` 2024-01-31 जैसी तारीख नहीं है।",
+	},
+	"first.voided": {
+		discordgo.EnglishUS: "%s no longer counts in <#%s>.",
+		discordgo.SpanishES: "El %s ya no cuenta en <#%s>.",
+		discordgo.Hindi: "%s अब <#%s> में नहीं गिना जाता।",
+	},
+	"first.voidFailed": {
+		discordgo.EnglishUS: "Couldn't void the day.",
+		discordgo.SpanishES: "No se pudo anular el día.",
+		discordgo.Hindi: "दिन रद्द नहीं किया जा सका।",
+	},
+	"first.unvoided": {
+		discordgo.EnglishUS: "%s counts in <#%s> again.",
+		discordgo.SpanishES: "El %s vuelve a contar en <#%s>.",
+		discordgo.Hindi: "%s फिर से <#%s> में गिना जाता है।",
+	},
+	"first.unvoidFailed": {
+		discordgo.EnglishUS: "Couldn't count the day again.",
+		discordgo.SpanishES: "No se pudo volver a contar el día.",
+		discordgo.Hindi: "दिन को फिर से नहीं गिना जा सका।",
+	},
+	"first.timeEntry": {
+		discordgo.EnglishUS: "%d. <@%s>: **%d** ms on [%s](https://discord.com/channels/%s/%s/%s)\n",
+		discordgo.SpanishES: "%d. <@%s>: **%d** ms el [%s](https://discord.com/channels/%s/%s/%s)\n",
+		discordgo.Hindi: "%d. <@%s>: **%d** ms, [%s](https://discord.com/channels/%s/%s/%s)\n",
+	},
+
+	// Imagen
+	"imagen.generated": {
+		discordgo.EnglishUS: "-# Generated in %0.1f seconds\n`%s`",
+		discordgo.SpanishES: "-# Generada en %0.1f segundos\n`%s`",
+		discordgo.Hindi: "-# %0.1f सेकंड में बनाया गया\n`%s`",
+	},
+	"imagen.noImages": {
+		discordgo.EnglishUS: "`%s`\nNo images were generated.",
+		discordgo.SpanishES: "`%s`\nNo se generó ninguna imagen.",
+		discordgo.Hindi: "`%s`\nकोई चित्र नहीं बना।",
+	},
+
+	// Send
+	"send.scheduled": {
+		discordgo.EnglishUS: "Message `%s` scheduled for <t:%d:F> (<t:%d:R>).",
+		discordgo.SpanishES: "Mensaje `%s` programado para <t:%d:F> (<t:%d:R>).",
+		discordgo.Hindi: "संदेश `%s` <t:%d:F> (<t:%d:R>) के लिए शेड्यूल हो गया।",
+	},
+	"send.invalidColor": {
+		discordgo.EnglishUS: "`%s` is not a hex color code.",
+		discordgo.SpanishES: "`%s` no es un código de color hexadecimal.",
+		discordgo.Hindi: "`%s` कोई हेक्स रंग कोड नहीं है।",
+	},
+	"send.noStorage": {
+		discordgo.EnglishUS: "Scheduled messages can't have files because file storage isn't set up.",
+		discordgo.SpanishES: "Los mensajes programados no pueden tener archivos porque el almacenamiento no está configurado.",
+		discordgo.Hindi: "शेड्यूल किए गए संदेशों में फ़ाइलें नहीं हो सकतीं क्योंकि फ़ाइल स्टोरेज सेट नहीं है।",
+	},
+	"send.expired": {
+		discordgo.EnglishUS: "This has expired, please use /send again.",
+		discordgo.SpanishES: "Esto caducó, usa /send de nuevo.",
+		discordgo.Hindi: "यह समाप्त हो गया है, कृपया /send फिर से इस्तेमाल करें।",
+	},
+	"send.modalTitle": {
+		discordgo.EnglishUS: "Scheduled message",
+		discordgo.SpanishES: "Mensaje programado",
+		discordgo.Hindi: "शेड्यूल किया गया संदेश",
+	},
+	"send.modalContent": {
+		discordgo.EnglishUS: "Content",
+		discordgo.SpanishES: "Contenido",
+		discordgo.Hindi: "सामग्री",
+	},
+	"send.unknownChannel": {
+		discordgo.EnglishUS: "The channel <#%s> doesn't exist or the bot can't see it.",
+		discordgo.SpanishES: "El canal <#%s> no existe o el bot no puede verlo.",
+		discordgo.Hindi: "चैनल <#%s> मौजूद नहीं है या बॉट उसे नहीं देख सकता।",
+	},
+	"send.threadLocked": {
+		discordgo.EnglishUS: "The thread <#%s> is locked.",
+		discordgo.SpanishES: "El hilo <#%s> está bloqueado.",
+		discordgo.Hindi: "थ्रेड <#%s> लॉक है।",
+	},
+	"send.botCannotSend": {
+		discordgo.EnglishUS: "The bot can't send this message in <#%s>.",
+		discordgo.SpanishES: "El bot no puede enviar este mensaje en <#%s>.",
+		discordgo.Hindi: "बॉट <#%s> में यह संदेश नहीं भेज सकता।",
+	},
+	"send.userCannotSend": {
+		discordgo.EnglishUS: "You can't send this message in <#%s>.",
+		discordgo.SpanishES: "No puedes enviar este mensaje en <#%s>.",
+		discordgo.Hindi: "आप <#%s> में यह संदेश नहीं भेज सकते।",
+	},
+	"send.confirm": {
+		discordgo.EnglishUS: "Send this message at <t:%d:F> (<t:%d:R>)?",
+		discordgo.SpanishES: "¿Enviar este mensaje el <t:%d:F> (<t:%d:R>)?",
+		discordgo.Hindi: "यह संदेश <t:%d:F> (<t:%d:R>) पर भेजें?",
+	},
+	"send.confirmRepeat": {
+		discordgo.EnglishUS: "Send this message at <t:%d:F> (<t:%d:R>), then repeat on `%s` in %s?",
+		discordgo.SpanishES: "¿Enviar este mensaje el <t:%d:F> (<t:%d:R>) y repetirlo según `%s` en %s?",
+		discordgo.Hindi: "यह संदेश <t:%d:F> (<t:%d:R>) पर भेजें, फिर %[4]s में `%[3]s` के अनुसार दोहराएँ?",
+	},
+	"send.invalidRepeat": {
+		discordgo.EnglishUS: "Couldn't understand the repeat `%s`. Use `daily 09:00`, `weekly mon,fri 17:00` or a cron expression like `0 9 * * 1-5`.",
+		discordgo.SpanishES: "No se entendió la repetición `%s`. Usa `daily 09:00`, `weekly mon,fri 17:00` o una expresión cron como `0 9 * * 1-5`.",
+		discordgo.Hindi: "दोहराव `%s` समझ नहीं आया। `daily 09:00`, `weekly mon,fri 17:00` या `0 9 * * 1-5` जैसा cron एक्सप्रेशन इस्तेमाल करें।",
+	},
+	"send.neverRepeats": {
+		discordgo.EnglishUS: "The repeat `%s` never fires before it ends.",
+		discordgo.SpanishES: "La repetición `%s` nunca ocurre antes de terminar.",
+		discordgo.Hindi: "दोहराव `%s` खत्म होने से पहले कभी नहीं चलता।",
+	},
+	"send.nothingToEdit": {
+		discordgo.EnglishUS: "Give a new `time` or `content` to change.",
+		discordgo.SpanishES: "Indica un nuevo `time` o `content` para cambiar.",
+		discordgo.Hindi: "बदलने के लिए नया `time` या `content` दें।",
+	},
+	"send.notFound": {
+		discordgo.EnglishUS: "There is no scheduled message `%s` that you can manage.",
+		discordgo.SpanishES: "No hay ningún mensaje programado `%s` que puedas gestionar.",
+		discordgo.Hindi: "ऐसा कोई शेड्यूल किया गया संदेश `%s` नहीं है जिसे आप प्रबंधित कर सकें।",
+	},
+	"send.wrongStatus": {
+		discordgo.EnglishUS: "Scheduled message `%s` is %s.",
+		discordgo.SpanishES: "El mensaje programado `%s` está en estado %s.",
+		discordgo.Hindi: "शेड्यूल किया गया संदेश `%s` की स्थिति %s है।",
+	},
+	"send.paused": {
+		discordgo.EnglishUS: "Paused scheduled message `%s`.",
+		discordgo.SpanishES: "Se pausó el mensaje programado `%s`.",
+		discordgo.Hindi: "शेड्यूल किया गया संदेश `%s` रोक दिया गया।",
+	},
+	"send.resumed": {
+		discordgo.EnglishUS: "Resumed scheduled message `%s`, which is next sent at <t:%d:F> (<t:%d:R>).",
+		discordgo.SpanishES: "Se reanudó el mensaje programado `%s`, que se enviará el <t:%d:F> (<t:%d:R>).",
+		discordgo.Hindi: "शेड्यूल किया गया संदेश `%s` फिर से शुरू हुआ, अगली बार <t:%d:F> (<t:%d:R>) पर भेजा जाएगा।",
+	},
+	"send.edited": {
+		discordgo.EnglishUS: "Scheduled message `%s` is now sent at <t:%d:F> (<t:%d:R>).",
+		discordgo.SpanishES: "El mensaje programado `%s` ahora se enviará el <t:%d:F> (<t:%d:R>).",
+		discordgo.Hindi: "शेड्यूल किया गया संदेश `%s` अब <t:%d:F> (<t:%d:R>) पर भेजा जाएगा।",
+	},
+	"send.cancelledJob": {
+		discordgo.EnglishUS: "Cancelled scheduled message `%s`, which was due <t:%d:R>.",
+		discordgo.SpanishES: "Se canceló el mensaje programado `%s`, que debía enviarse <t:%d:R>.",
+		discordgo.Hindi: "शेड्यूल किया गया संदेश `%s` रद्द कर दिया गया, जो <t:%d:R> भेजा जाना था।",
+	},
+	"send.listHeader": {
+		discordgo.EnglishUS: "**Scheduled messages** (page %d of %d)",
+		discordgo.SpanishES: "**Mensajes programados** (página %d de %d)",
+		discordgo.Hindi: "**शेड्यूल किए गए संदेश** (पेज %d / %d)",
+	},
+	"send.listEntry": {
+		discordgo.EnglishUS: "`%s` <t:%d:F> (<t:%d:R>) in <#%s> by <@%s>",
+		discordgo.SpanishES: "`%s` <t:%d:F> (<t:%d:R>) en <#%s> por <@%s>",
+		discordgo.Hindi: "`%s` <t:%d:F> (<t:%d:R>) <#%s> में, <@%s> द्वारा",
+	},
+	"send.listEmpty": {
+		discordgo.EnglishUS: "There are no scheduled messages.",
+		discordgo.SpanishES: "No hay mensajes programados.",
+		discordgo.Hindi: "कोई शेड्यूल किया गया संदेश नहीं है।",
+	},
+	"send.notManager": {
+		discordgo.EnglishUS: "Only server managers can list everyone's scheduled messages.",
+		discordgo.SpanishES: "Solo los administradores del servidor pueden listar los mensajes programados de todos.",
+		discordgo.Hindi: "सिर्फ़ सर्वर मैनेजर ही सभी के शेड्यूल किए गए संदेश देख सकते हैं।",
+	},
+	"send.confirmButton": {
+		discordgo.EnglishUS: "Schedule",
+		discordgo.SpanishES: "Programar",
+		discordgo.Hindi: "शेड्यूल करें",
+	},
+	"send.cancelButton": {
+		discordgo.EnglishUS: "Cancel",
+		discordgo.SpanishES: "Cancelar",
+		discordgo.Hindi: "रद्द करें",
+	},
+	"send.cancelled": {
+		discordgo.EnglishUS: "The message was not scheduled.",
+		discordgo.SpanishES: "El mensaje no se programó.",
+		discordgo.Hindi: "संदेश शेड्यूल नहीं किया गया।",
+	},
+	"send.scheduleFailed": {
+		discordgo.EnglishUS: "The message could not be scheduled.",
+		discordgo.SpanishES: "No se pudo programar el mensaje.",
+		discordgo.Hindi: "संदेश शेड्यूल नहीं हो सका।",
+	},
+
+	// Scheduled jobs, keyed by job kind and outcome
+	"remind.scheduled": {
+		discordgo.EnglishUS: "I'll remind you <t:%d:F> (<t:%d:R>).",
+		discordgo.SpanishES: "Te lo recordaré el <t:%d:F> (<t:%d:R>).",
+		discordgo.Hindi: "मैं आपको <t:%d:F> (<t:%d:R>) याद दिलाऊँगा।",
+	},
+	"remind.scheduleFailed": {
+		discordgo.EnglishUS: "The reminder could not be scheduled.",
+		discordgo.SpanishES: "No se pudo programar el recordatorio.",
+		discordgo.Hindi: "रिमाइंडर शेड्यूल नहीं हो सका।",
+	},
+	"remind.past": {
+		discordgo.EnglishUS: "<t:%d:F> has already passed.",
+		discordgo.SpanishES: "<t:%d:F> ya pasó.",
+		discordgo.Hindi: "<t:%d:F> बीत चुका है।",
+	},
+	"remind.message": {
+		discordgo.EnglishUS: "⏰ <@%s> Reminder: %s",
+		discordgo.SpanishES: "⏰ <@%s> Recordatorio: %s",
+		discordgo.Hindi: "⏰ <@%s> रिमाइंडर: %s",
+	},
+	"remind.defaultText": {
+		discordgo.EnglishUS: "this message",
+		discordgo.SpanishES: "este mensaje",
+		discordgo.Hindi: "यह संदेश",
+	},
+	"remind.modalTitle": {
+		discordgo.EnglishUS: "Remind me",
+		discordgo.SpanishES: "Recuérdame",
+		discordgo.Hindi: "मुझे याद दिलाएँ",
+	},
+	"remind.modalWhen": {
+		discordgo.EnglishUS: "When",
+		discordgo.SpanishES: "Cuándo",
+		discordgo.Hindi: "कब",
+	},
+	"remind.modalText": {
+		discordgo.EnglishUS: "Note",
+		discordgo.SpanishES: "Nota",
+		discordgo.Hindi: "नोट",
+	},
+	"remind.snooze10m": {
+		discordgo.EnglishUS: "Snooze 10 minutes",
+		discordgo.SpanishES: "Posponer 10 minutos",
+		discordgo.Hindi: "10 मिनट बाद",
+	},
+	"remind.snooze1h": {
+		discordgo.EnglishUS: "Snooze 1 hour",
+		discordgo.SpanishES: "Posponer 1 hora",
+		discordgo.Hindi: "1 घंटे बाद",
+	},
+	"remind.snooze1d": {
+		discordgo.EnglishUS: "Snooze 1 day",
+		discordgo.SpanishES: "Posponer 1 día",
+		discordgo.Hindi: "1 दिन बाद",
+	},
+	"remind.snoozed": {
+		discordgo.EnglishUS: "Snoozed until <t:%d:R>.",
+		discordgo.SpanishES: "Pospuesto hasta <t:%d:R>.",
+		discordgo.Hindi: "<t:%d:R> तक के लिए टाल दिया गया।",
+	},
+	"remind.notYours": {
+		discordgo.EnglishUS: "Only the person being reminded can snooze this.",
+		discordgo.SpanishES: "Solo la persona a quien se le recuerda puede posponer esto.",
+		discordgo.Hindi: "इसे सिर्फ़ वही टाल सकता है जिसे याद दिलाया जा रहा है।",
+	},
+	"snowflake.invalid": {
+		discordgo.EnglishUS: "`%s` is not a Discord ID.",
+		discordgo.SpanishES: "`%s` no es un ID de Discord.",
+		discordgo.Hindi: "`%s` कोई Discord ID नहीं है।",
+	},
+	"snowflake.beforeEpoch": {
+		discordgo.EnglishUS: "Discord IDs start in 2015.",
+		discordgo.SpanishES: "Los IDs de Discord empiezan en 2015.",
+		discordgo.Hindi: "Discord ID 2015 से शुरू होती हैं।",
+	},
+	"snowflake.decoded": {
+		discordgo.EnglishUS: "`%d`\nTime: <t:%d:F> (`%d` ms, `%s`)\nWorker: `%d`\nProcess: `%d`\nIncrement: `%d`",
+		discordgo.SpanishES: "`%d`\nHora: <t:%d:F> (`%d` ms, `%s`)\nWorker: `%d`\nProceso: `%d`\nIncremento: `%d`",
+		discordgo.Hindi: "`%d`\nसमय: <t:%d:F> (`%d` ms, `%s`)\nवर्कर: `%d`\nप्रोसेस: `%d`\nइंक्रीमेंट: `%d`",
+	},
+	"snowflake.encoded": {
+		discordgo.EnglishUS: "IDs from <t:%d:F> (`%d` ms)\nSmallest: `%d`\nLargest: `%d`",
+		discordgo.SpanishES: "IDs de <t:%d:F> (`%d` ms)\nMínimo: `%d`\nMáximo: `%d`",
+		discordgo.Hindi: "<t:%d:F> (`%d` ms) की ID\nसबसे छोटी: `%d`\nसबसे बड़ी: `%d`",
+	},
+	"snowflake.diff": {
+		discordgo.EnglishUS: "`%d` at `%d` ms\n`%d` at `%d` ms\nDifference: `%+d` ms",
+		discordgo.SpanishES: "`%d` a los `%d` ms\n`%d` a los `%d` ms\nDiferencia: `%+d` ms",
+		discordgo.Hindi: "`%d`, `%d` ms पर\n`%d`, `%d` ms पर\nअंतर: `%+d` ms",
+	},
+	"snowflake.first": {
+		discordgo.EnglishUS: "`%d` came first by %s.",
+		discordgo.SpanishES: "`%d` fue primero por %s.",
+		discordgo.Hindi: "`%d` %s पहले आया।",
+	},
+	"snowflake.sameMillisecond": {
+		discordgo.EnglishUS: "Both are from the same millisecond, so there is no telling which came first.",
+		discordgo.SpanishES: "Ambos son del mismo milisegundo, así que no se puede saber cuál fue primero.",
+		discordgo.Hindi: "दोनों एक ही मिलीसेकंड के हैं, इसलिए पता नहीं चल सकता कि पहले कौन आया।",
+	},
+	"userInfo.header": {
+		discordgo.EnglishUS: "<@%s> (`%s`)",
+		discordgo.SpanishES: "<@%s> (`%s`)",
+		discordgo.Hindi: "<@%s> (`%s`)",
+	},
+	"userInfo.created": {
+		discordgo.EnglishUS: "Account created <t:%d:F> (<t:%d:R>)",
+		discordgo.SpanishES: "Cuenta creada el <t:%d:F> (<t:%d:R>)",
+		discordgo.Hindi: "खाता <t:%d:F> को बनाया गया (<t:%d:R>)",
+	},
+	"userInfo.joined": {
+		discordgo.EnglishUS: "Joined the server <t:%d:F> (<t:%d:R>)",
+		discordgo.SpanishES: "Se unió al servidor el <t:%d:F> (<t:%d:R>)",
+		discordgo.Hindi: "सर्वर से <t:%d:F> को जुड़े (<t:%d:R>)",
+	},
+	"userInfo.roles": {
+		discordgo.EnglishUS: "Roles (%d): %s",
+		discordgo.SpanishES: "Roles (%d): %s",
+		discordgo.Hindi: "भूमिकाएँ (%d): %s",
+	},
+	"userInfo.noRoles": {
+		discordgo.EnglishUS: "No roles",
+		discordgo.SpanishES: "Sin roles",
+		discordgo.Hindi: "कोई भूमिका नहीं",
+	},
+	"userInfo.firstMessages": {
+		discordgo.EnglishUS: "First messages: %d, fastest `%d` ms into the day",
+		discordgo.SpanishES: "Primeros mensajes: %d, el más rápido a los `%d` ms del día",
+		discordgo.Hindi: "पहले संदेश: %d, सबसे तेज़ दिन शुरू होने के `%d` ms बाद",
+	},
+	"userInfo.share": {
+		discordgo.EnglishUS: "Share",
+		discordgo.SpanishES: "Compartir",
+		discordgo.Hindi: "साझा करें",
+	},
+	"timezone.invalid": {
+		discordgo.EnglishUS: "`%s` isn't an IANA timezone. Pick one from the list, like `America/New_York` or `Asia/Kolkata`.",
+		discordgo.SpanishES: "`%s` no es una zona horaria IANA. Elige una de la lista, como `America/New_York` o `Asia/Kolkata`.",
+		discordgo.Hindi: "`%s` कोई IANA टाइमज़ोन नहीं है। सूची में से कोई चुनें, जैसे `America/New_York` या `Asia/Kolkata`।",
+	},
+	"timezone.saveFailed": {
+		discordgo.EnglishUS: "Couldn't save the timezone. Try again later.",
+		discordgo.SpanishES: "No se pudo guardar la zona horaria. Inténtalo más tarde.",
+		discordgo.Hindi: "टाइमज़ोन सेव नहीं हो सका। बाद में फिर कोशिश करें।",
+	},
+	"timezone.notManager": {
+		discordgo.EnglishUS: "Only server managers can set the server's timezone.",
+		discordgo.SpanishES: "Solo los administradores del servidor pueden configurar su zona horaria.",
+		discordgo.Hindi: "सिर्फ़ सर्वर मैनेजर ही सर्वर का टाइमज़ोन सेट कर सकते हैं।",
+	},
+	"timezone.set": {
+		discordgo.EnglishUS: "Your timezone is now `%s`. It's %s there.",
+		discordgo.SpanishES: "Tu zona horaria ahora es `%s`. Allí son las %s.",
+		discordgo.Hindi: "आपका टाइमज़ोन अब `%s` है। वहाँ अभी %s है।",
+	},
+	"timezone.cleared": {
+		discordgo.EnglishUS: "Your timezone was removed, so the server's default `%s` is used.",
+		discordgo.SpanishES: "Se quitó tu zona horaria, así que se usa la predeterminada del servidor, `%s`.",
+		discordgo.Hindi: "आपका टाइमज़ोन हटा दिया गया, इसलिए सर्वर का डिफ़ॉल्ट `%s` इस्तेमाल होगा।",
+	},
+	"timezone.serverSet": {
+		discordgo.EnglishUS: "The server's default timezone is now `%s`.",
+		discordgo.SpanishES: "La zona horaria predeterminada del servidor ahora es `%s`.",
+		discordgo.Hindi: "सर्वर का डिफ़ॉल्ट टाइमज़ोन अब `%s` है।",
+	},
+	"timezone.show": {
+		discordgo.EnglishUS: "Your timezone: `%s`",
+		discordgo.SpanishES: "Tu zona horaria: `%s`",
+		discordgo.Hindi: "आपका टाइमज़ोन: `%s`",
+	},
+	"timezone.showUnset": {
+		discordgo.EnglishUS: "You haven't set a timezone. Use `/timezone set`.",
+		discordgo.SpanishES: "No has configurado una zona horaria. Usa `/timezone set`.",
+		discordgo.Hindi: "आपने कोई टाइमज़ोन सेट नहीं किया है। `/timezone set` इस्तेमाल करें।",
+	},
+	"timezone.showServer": {
+		discordgo.EnglishUS: "Server default: `%s`",
+		discordgo.SpanishES: "Predeterminada del servidor: `%s`",
+		discordgo.Hindi: "सर्वर डिफ़ॉल्ट: `%s`",
+	},
+	"timezone.now": {
+		discordgo.EnglishUS: "Times are read and shown in `%s`, where it's %s.",
+		discordgo.SpanishES: "Las horas se leen y muestran en `%s`, donde son las %s.",
+		discordgo.Hindi: "समय `%s` में पढ़े और दिखाए जाते हैं, जहाँ अभी %s है।",
+	},
+	"time.local": {
+		discordgo.EnglishUS: "-# %s in `%s`",
+		discordgo.SpanishES: "-# %s en `%s`",
+		discordgo.Hindi: "-# `%[2]s` में %[1]s",
+	},
+	"timestamp.header": {
+		discordgo.EnglishUS: "Message sent <t:%d:F>",
+		discordgo.SpanishES: "Mensaje enviado el <t:%d:F>",
+		discordgo.Hindi: "संदेश <t:%d:F> को भेजा गया",
+	},
+	"timestamp.unix": {
+		discordgo.EnglishUS: "Unix ms: `%d`",
+		discordgo.SpanishES: "Unix ms: `%d`",
+		discordgo.Hindi: "यूनिक्स ms: `%d`",
+	},
+	"timestamp.isoUTC": {
+		discordgo.EnglishUS: "ISO 8601 (UTC): `%s`",
+		discordgo.SpanishES: "ISO 8601 (UTC): `%s`",
+		discordgo.Hindi: "ISO 8601 (UTC): `%s`",
+	},
+	"timestamp.isoLocal": {
+		discordgo.EnglishUS: "ISO 8601 (%s): `%s`",
+		discordgo.SpanishES: "ISO 8601 (%s): `%s`",
+		discordgo.Hindi: "ISO 8601 (%s): `%s`",
+	},
+	"timestamp.age": {
+		discordgo.EnglishUS: "Age: %s (<t:%d:R>)",
+		discordgo.SpanishES: "Antigüedad: %s (<t:%d:R>)",
+		discordgo.Hindi: "उम्र: %s (<t:%d:R>)",
+	},
+	"timestamp.midnight": {
+		discordgo.EnglishUS: "Since midnight (%s): `%d` ms (%s)",
+		discordgo.SpanishES: "Desde la medianoche (%s): `%d` ms (%s)",
+		discordgo.Hindi: "आधी रात से (%s): `%d` ms (%s)",
+	},
+	"timestamp.format.t": {
+		discordgo.EnglishUS: "Short time",
+		discordgo.SpanishES: "Hora corta",
+		discordgo.Hindi: "छोटा समय",
+	},
+	"timestamp.format.T": {
+		discordgo.EnglishUS: "Long time",
+		discordgo.SpanishES: "Hora larga",
+		discordgo.Hindi: "लंबा समय",
+	},
+	"timestamp.format.d": {
+		discordgo.EnglishUS: "Short date",
+		discordgo.SpanishES: "Fecha corta",
+		discordgo.Hindi: "छोटी तारीख",
+	},
+	"timestamp.format.D": {
+		discordgo.EnglishUS: "Long date",
+		discordgo.SpanishES: "Fecha larga",
+		discordgo.Hindi: "लंबी तारीख",
+	},
+	"timestamp.format.f": {
+		discordgo.EnglishUS: "Short date/time",
+		discordgo.SpanishES: "Fecha y hora cortas",
+		discordgo.Hindi: "छोटी तारीख/समय",
+	},
+	"timestamp.format.F": {
+		discordgo.EnglishUS: "Long date/time",
+		discordgo.SpanishES: "Fecha y hora largas",
+		discordgo.Hindi: "लंबी तारीख/समय",
+	},
+	"timestamp.format.R": {
+		discordgo.EnglishUS: "Relative",
+		discordgo.SpanishES: "Relativo",
+		discordgo.Hindi: "सापेक्ष",
+	},
+	"timestamp.format.ms": {
+		discordgo.EnglishUS: "Unix ms",
+		discordgo.SpanishES: "Unix ms",
+		discordgo.Hindi: "यूनिक्स ms",
+	},
+	"timestamp.format.iso": {
+		discordgo.EnglishUS: "ISO 8601",
+		discordgo.SpanishES: "ISO 8601",
+		discordgo.Hindi: "ISO 8601",
+	},
+	"time.shortEpoch": {
+		discordgo.EnglishUS: "`%s` is too short to be Unix epoch time in milliseconds. For a time from now, try something like `in %[1]sm`.",
+		discordgo.SpanishES: "`%s` es demasiado corto para ser tiempo Unix en milisegundos. Para un tiempo a partir de ahora, prueba algo como `in %[1]sm`.",
+		discordgo.Hindi: "`%s` मिलीसेकंड में यूनिक्स समय होने के लिए बहुत छोटा है। अभी से किसी समय के लिए `in %[1]sm` जैसा कुछ आज़माएँ।",
+	},
+	"time.invalid": {
+		discordgo.EnglishUS: "Couldn't understand the time `%s`. Try something like `in 2h30m`, `tomorrow 9am`, `fri 17:00`, `2026-12-31 23:59:59.500` or Unix epoch time in milliseconds.",
+		discordgo.SpanishES: "No se entendió la hora `%s`. Prueba algo como `in 2h30m`, `tomorrow 9am`, `fri 17:00`, `2026-12-31 23:59:59.500` o tiempo Unix en milisegundos.",
+		discordgo.Hindi: "समय `%s` समझ नहीं आया। `in 2h30m`, `tomorrow 9am`, `fri 17:00`, `2026-12-31 23:59:59.500` या मिलीसेकंड में यूनिक्स समय जैसा कुछ आज़माएँ।",
+	},
+	"job.send.sent": {
+		discordgo.EnglishUS: "Your message scheduled for <t:%d:F> was sent in <#%s>, %+d ms from its time.",
+		discordgo.SpanishES: "Tu mensaje programado para <t:%d:F> se envió en <#%s>, a %+d ms de su hora.",
+		discordgo.Hindi: "<t:%d:F> के लिए शेड्यूल किया गया आपका संदेश <#%s> में भेज दिया गया, अपने समय से %+d ms पर।",
+	},
+	"job.send.skipped": {
+		discordgo.EnglishUS: "Your message scheduled for <t:%d:F> in <#%s> was skipped because the bot was offline.",
+		discordgo.SpanishES: "Tu mensaje programado para <t:%d:F> en <#%s> se omitió porque el bot estaba desconectado.",
+		discordgo.Hindi: "<t:%d:F> के लिए <#%s> में शेड्यूल किया गया आपका संदेश छोड़ दिया गया क्योंकि बॉट ऑफ़लाइन था।",
+	},
+	"job.remind.sent": {
+		discordgo.EnglishUS: "Your reminder for <t:%[1]d:F> was delivered.",
+		discordgo.SpanishES: "Tu recordatorio para <t:%[1]d:F> se entregó.",
+		discordgo.Hindi: "<t:%[1]d:F> का आपका रिमाइंडर पहुँचा दिया गया।",
+	},
+	"job.remind.skipped": {
+		discordgo.EnglishUS: "Your reminder for <t:%[1]d:F> was skipped because the bot was offline.",
+		discordgo.SpanishES: "Tu recordatorio para <t:%[1]d:F> se omitió porque el bot estaba desconectado.",
+		discordgo.Hindi: "<t:%[1]d:F> का आपका रिमाइंडर छोड़ दिया गया क्योंकि बॉट ऑफ़लाइन था।",
+	},
+	"job.remind.failed": {
+		discordgo.EnglishUS: "Your reminder for <t:%[1]d:F> could not be delivered: %[3]s",
+		discordgo.SpanishES: "Tu recordatorio para <t:%[1]d:F> no se pudo entregar: %[3]s",
+		discordgo.Hindi: "<t:%[1]d:F> का आपका रिमाइंडर नहीं पहुँचाया जा सका: %[3]s",
+	},
+	"job.firstSummary.skipped": {
+		discordgo.EnglishUS: "The first message summary for <t:%d:F> in <#%s> was skipped because the bot was offline.",
+		discordgo.SpanishES: "El resumen de primer mensaje para <t:%d:F> en <#%s> se omitió porque el bot estaba desconectado.",
+		discordgo.Hindi: "<t:%d:F> के लिए <#%s> में फ़र्स्ट मैसेज सारांश छोड़ दिया गया क्योंकि बॉट ऑफ़लाइन था।",
+	},
+	"job.firstSummary.failed": {
+		discordgo.EnglishUS: "The first message summary for <t:%d:F> in <#%s> could not be posted: %s",
+		discordgo.SpanishES: "El resumen de primer mensaje para <t:%d:F> en <#%s> no se pudo publicar: %s",
+		discordgo.Hindi: "<t:%d:F> के लिए <#%s> में फ़र्स्ट मैसेज सारांश पोस्ट नहीं किया जा सका: %s",
+	},
+	"job.send.failed": {
+		discordgo.EnglishUS: "Your message scheduled for <t:%d:F> in <#%s> could not be sent: %s",
+		discordgo.SpanishES: "Tu mensaje programado para <t:%d:F> en <#%s> no se pudo enviar: %s",
+		discordgo.Hindi: "<t:%d:F> के लिए <#%s> में शेड्यूल किया गया आपका संदेश नहीं भेजा जा सका: %s",
+	},
+
+	// Urban Dictionary
+	"ud.noResults": {
+		discordgo.EnglishUS: "No results were found.",
+		discordgo.SpanishES: "No se encontraron resultados.",
+		discordgo.Hindi: "कोई परिणाम नहीं मिला।",
+	},
+	"ud.definition": {
+		discordgo.EnglishUS: "Definition",
+		discordgo.SpanishES: "Definición",
+		discordgo.Hindi: "परिभाषा",
+	},
+	"ud.example": {
+		discordgo.EnglishUS: "Example",
+		discordgo.SpanishES: "Ejemplo",
+		discordgo.Hindi: "उदाहरण",
+	},
+	"ud.author": {
+		discordgo.EnglishUS: "Author",
+		discordgo.SpanishES: "Autor",
+		discordgo.Hindi: "लेखक",
+	},
+	"ud.date": {
+		discordgo.EnglishUS: "Date",
+		discordgo.SpanishES: "Fecha",
+		discordgo.Hindi: "तारीख",
+	},
+
+	// YouTube
+	"yt.notInGuild": {
+		discordgo.EnglishUS: "You must be in a guild voice channel to use this command.",
+		discordgo.SpanishES: "Debes estar en un canal de voz de un servidor para usar este comando.",
+		discordgo.Hindi: "इस कमांड के लिए आपको किसी सर्वर के वॉइस चैनल में होना चाहिए।",
+	},
+	"yt.notInVoice": {
+		discordgo.EnglishUS: "You must be in a voice channel to use this command.",
+		discordgo.SpanishES: "Debes estar en un canal de voz para usar este comando.",
+		discordgo.Hindi: "इस कमांड के लिए आपको वॉइस चैनल में होना चाहिए।",
+	},
+	"yt.searchFailed": {
+		discordgo.EnglishUS: "Search request failed.",
+		discordgo.SpanishES: "La búsqueda falló.",
+		discordgo.Hindi: "खोज विफल रही।",
+	},
+	"yt.noResults": {
+		discordgo.EnglishUS: "No results found for %s.",
+		discordgo.SpanishES: "No hay resultados para %s.",
+		discordgo.Hindi: "%s के लिए कोई परिणाम नहीं मिला।",
+	},
+	"yt.results": {
+		discordgo.EnglishUS: "Results for %s",
+		discordgo.SpanishES: "Resultados para %s",
+		discordgo.Hindi: "%s के परिणाम",
+	},
+	"yt.playing": {
+		discordgo.EnglishUS: "Playing",
+		discordgo.SpanishES: "Reproduciendo",
+		discordgo.Hindi: "चल रहा है",
+	},
+}
+
+// localizedError is an error whose message can be shown to users in their language
+type localizedError struct {
+	Key string
+	Args []any
+}
+
+func (e localizedError) Error() string {
+	return translate(DEFAULT_LOCALE, e.Key, e.Args...)
+}
+
+func hasLocale(locale discordgo.Locale) bool {
+	if fallback, ok := LOCALE_FALLBACKS[locale]; ok {
+		locale = fallback
+	}
+	if locale == DEFAULT_LOCALE {
+		return true
+	}
+	for _, translations := range messages {
+		if _, ok := translations[locale]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// translate formats the message with the given key in a locale, falling back
+// to the default locale and then to the key itself
+func translate(locale discordgo.Locale, key string, args ...any) string {
+	translations := messages[key]
+	message, ok := translations[locale]
+	if !ok {
+		if fallback, isFallback := LOCALE_FALLBACKS[locale]; isFallback {
+			message, ok = translations[fallback]
+		}
+	}
+	if !ok {
+		if message, ok = translations[DEFAULT_LOCALE]; !ok {
+			message = key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// interactionLocale picks the user's locale if it is supported, then the
+// guild's preferred locale
+func interactionLocale(i *discordgo.InteractionCreate) discordgo.Locale {
+	if hasLocale(i.Locale) {
+		return i.Locale
+	}
+	if i.GuildLocale != nil && hasLocale(*i.GuildLocale) {
+		return *i.GuildLocale
+	}
+	return DEFAULT_LOCALE
+}
+
+// guildLocale returns the preferred locale of a guild for messages that are
+// not replies to an interaction
+func guildLocale(s *discordgo.Session, guildID string) discordgo.Locale {
+	if guild, err := s.State.Guild(guildID); err == nil && hasLocale(discordgo.Locale(guild.PreferredLocale)) {
+		return discordgo.Locale(guild.PreferredLocale)
+	}
+	return DEFAULT_LOCALE
+}
+
+// tr translates a message for the user of an interaction
+func tr(i *discordgo.InteractionCreate, key string, args ...any) string {
+	return translate(interactionLocale(i), key, args...)
+}
+
+// trError translates err if it is a localizedError
+func trError(i *discordgo.InteractionCreate, err error) string {
+	if e, ok := err.(localizedError); ok {
+		return tr(i, e.Key, e.Args...)
+	}
+	return err.Error()
+}
+
+// localizationsFor returns the translations of command metadata, including
+// locales that fall back to a translated one
+func localizationsFor(key string) map[discordgo.Locale]string {
+	translations, ok := messages["command." + key]
+	if !ok {
+		return nil
+	}
+	localizations := make(map[discordgo.Locale]string, len(translations))
+	for locale, translation := range translations {
+		localizations[locale] = translation
+	}
+	for locale, fallback := range LOCALE_FALLBACKS {
+		if translation, ok := translations[fallback]; ok && fallback != DEFAULT_LOCALE {
+			localizations[locale] = translation
+		}
+	}
+	return localizations
+}
+
+// localizeCommand fills the localizations of a command and its options from
+// the message catalog
+func localizeCommand(command *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+	localizations := localizationsFor(command.Name)
+	if localizations != nil {
+		if command.Type == discordgo.UserApplicationCommand || command.Type == discordgo.MessageApplicationCommand {
+			command.NameLocalizations = &localizations
+		} else {
+			command.DescriptionLocalizations = &localizations
+		}
+	}
+	localizeOptions(command.Name, command.Options)
+	return command
+}
+
+func localizeOptions(prefix string, options []*discordgo.ApplicationCommandOption) {
+	for _, option := range options {
+		key := prefix + "." + option.Name
+		option.DescriptionLocalizations = localizationsFor(key)
+		localizeOptions(key, option.Options)
+	}
+}
@@ -0,0 +1,374 @@
+package interactions
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Command options are described by structs whose fields carry these tags:
+//
+//	option       option name, fields without it are ignored
+//	description  description shown in the Discord client
+//	required     "true" if the option must be given
+//	default      value used when the option is omitted
+//	min, max     value range for numbers, length range for strings
+//	choices      comma separated list of allowed string values
+//	autocomplete "true" if values are suggested by an autocomplete handler
+//	channels     comma separated list of channel types a channel option takes
+//
+// A field holding a pointer to another options struct is a subcommand, or a
+// subcommand group if that struct only holds subcommands. It stays nil unless
+// that subcommand was used. commandOptions builds the command definition and
+// parseOptions decodes an interaction, both from the same struct.
+
+// Channel type names for the channels tag
+var CHANNEL_TYPE_NAMES = map[string]discordgo.ChannelType{
+	"text": discordgo.ChannelTypeGuildText,
+	"voice": discordgo.ChannelTypeGuildVoice,
+	"category": discordgo.ChannelTypeGuildCategory,
+	"news": discordgo.ChannelTypeGuildNews,
+	"newsThread": discordgo.ChannelTypeGuildNewsThread,
+	"publicThread": discordgo.ChannelTypeGuildPublicThread,
+	"privateThread": discordgo.ChannelTypeGuildPrivateThread,
+	"stage": discordgo.ChannelTypeGuildStageVoice,
+	"forum": discordgo.ChannelTypeGuildForum,
+	"media": discordgo.ChannelTypeGuildMedia,
+}
+
+var (
+	userOptionType = reflect.TypeOf((*discordgo.User)(nil))
+	memberOptionType = reflect.TypeOf((*discordgo.Member)(nil))
+	channelOptionType = reflect.TypeOf((*discordgo.Channel)(nil))
+	roleOptionType = reflect.TypeOf((*discordgo.Role)(nil))
+	attachmentOptionType = reflect.TypeOf((*discordgo.MessageAttachment)(nil))
+)
+
+func optionTypeOf(t reflect.Type) (discordgo.ApplicationCommandOptionType, bool) {
+	switch t {
+	case userOptionType, memberOptionType:
+		return discordgo.ApplicationCommandOptionUser, true
+	case channelOptionType:
+		return discordgo.ApplicationCommandOptionChannel, true
+	case roleOptionType:
+		return discordgo.ApplicationCommandOptionRole, true
+	case attachmentOptionType:
+		return discordgo.ApplicationCommandOptionAttachment, true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return discordgo.ApplicationCommandOptionString, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return discordgo.ApplicationCommandOptionInteger, true
+	case reflect.Float32, reflect.Float64:
+		return discordgo.ApplicationCommandOptionNumber, true
+	case reflect.Bool:
+		return discordgo.ApplicationCommandOptionBoolean, true
+	case reflect.Pointer:
+		if t.Elem().Kind() == reflect.Struct {
+			if isSubCommandGroup(t.Elem()) {
+				return discordgo.ApplicationCommandOptionSubCommandGroup, true
+			}
+			return discordgo.ApplicationCommandOptionSubCommand, true
+		}
+	}
+	return 0, false
+}
+
+// isSubCommandGroup reports whether every option of t is a subcommand
+func isSubCommandGroup(t reflect.Type) bool {
+	found := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("option") == "" {
+			continue
+		}
+		if field.Type.Kind() != reflect.Pointer || field.Type.Elem().Kind() != reflect.Struct {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+func isSubCommandType(optionType discordgo.ApplicationCommandOptionType) bool {
+	return optionType == discordgo.ApplicationCommandOptionSubCommand || optionType == discordgo.ApplicationCommandOptionSubCommandGroup
+}
+
+// commandOptions builds the option definitions for an options struct
+func commandOptions(v any) []*discordgo.ApplicationCommandOption {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("command options must be a struct, got %s", t))
+	}
+	var options []*discordgo.ApplicationCommandOption
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("option")
+		if name == "" {
+			continue
+		}
+		optionType, ok := optionTypeOf(field.Type)
+		if !ok {
+			panic(fmt.Sprintf("option %q has unsupported type %s", name, field.Type))
+		}
+		option := &discordgo.ApplicationCommandOption{
+			Type: optionType,
+			Name: name,
+			Description: field.Tag.Get("description"),
+			Required: field.Tag.Get("required") == "true",
+			Autocomplete: field.Tag.Get("autocomplete") == "true",
+		}
+		if isSubCommandType(optionType) {
+			option.Options = commandOptions(reflect.Zero(field.Type.Elem()).Interface())
+		}
+		if minTag, ok := field.Tag.Lookup("min"); ok {
+			switch optionType {
+			case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+				minValue := mustParseFloat(name, minTag)
+				option.MinValue = &minValue
+			case discordgo.ApplicationCommandOptionString:
+				minLength := int(mustParseFloat(name, minTag))
+				option.MinLength = &minLength
+			}
+		}
+		if maxTag, ok := field.Tag.Lookup("max"); ok {
+			switch optionType {
+			case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+				option.MaxValue = mustParseFloat(name, maxTag)
+			case discordgo.ApplicationCommandOptionString:
+				option.MaxLength = int(mustParseFloat(name, maxTag))
+			}
+		}
+		if channels, ok := field.Tag.Lookup("channels"); ok {
+			for _, channelName := range strings.Split(channels, ",") {
+				channelType, ok := CHANNEL_TYPE_NAMES[channelName]
+				if !ok {
+					panic(fmt.Sprintf("option %q has unknown channel type %q", name, channelName))
+				}
+				option.ChannelTypes = append(option.ChannelTypes, channelType)
+			}
+		}
+		if choices, ok := field.Tag.Lookup("choices"); ok {
+			for _, choice := range strings.Split(choices, ",") {
+				option.Choices = append(option.Choices, &discordgo.ApplicationCommandOptionChoice{
+					Name: choice,
+					Value: choice,
+				})
+			}
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+func mustParseFloat(name, value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("option %q has invalid bound %q", name, value))
+	}
+	return f
+}
+
+// parseOptions decodes the options of an application command into dst, which
+// must be a pointer to an options struct
+func parseOptions(data discordgo.ApplicationCommandInteractionData, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("options destination must be a non-nil struct pointer, got %T", dst)
+	}
+	resolved := data.Resolved
+	if resolved == nil {
+		resolved = &discordgo.ApplicationCommandInteractionDataResolved{}
+	}
+	return decodeOptions(data.Options, resolved, v.Elem())
+}
+
+func decodeOptions(options []*discordgo.ApplicationCommandInteractionDataOption, resolved *discordgo.ApplicationCommandInteractionDataResolved, v reflect.Value) error {
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, option := range options {
+		optionMap[option.Name] = option
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("option")
+		if name == "" {
+			continue
+		}
+		optionType, ok := optionTypeOf(field.Type)
+		if !ok {
+			return fmt.Errorf("option %q has unsupported type %s", name, field.Type)
+		}
+		fieldValue := v.Field(i)
+		option, ok := optionMap[name]
+		if !ok {
+			if isSubCommandType(optionType) {
+				continue
+			}
+			if field.Tag.Get("required") == "true" {
+				return localizedError{"option.missing", []any{name}}
+			}
+			if defaultValue, ok := field.Tag.Lookup("default"); ok {
+				if err := setDefaultOption(fieldValue, defaultValue); err != nil {
+					return fmt.Errorf("option %q has invalid default: %w", name, err)
+				}
+			}
+			continue
+		}
+		if option.Type != optionType {
+			return localizedError{"option.type", []any{name, optionType, option.Type}}
+		}
+		switch optionType {
+		case discordgo.ApplicationCommandOptionSubCommand, discordgo.ApplicationCommandOptionSubCommandGroup:
+			subCommand := reflect.New(field.Type.Elem())
+			if err := decodeOptions(option.Options, resolved, subCommand.Elem()); err != nil {
+				return err
+			}
+			fieldValue.Set(subCommand)
+		case discordgo.ApplicationCommandOptionString:
+			value := option.StringValue()
+			if err := checkOptionRange(field, name, float64(len([]rune(value))), "option.minLength", "option.maxLength"); err != nil {
+				return err
+			}
+			if choices, ok := field.Tag.Lookup("choices"); ok && !slices.Contains(strings.Split(choices, ","), value) {
+				return localizedError{"option.choices", []any{name, choices}}
+			}
+			fieldValue.SetString(value)
+		case discordgo.ApplicationCommandOptionInteger:
+			value := option.IntValue()
+			if err := checkOptionRange(field, name, float64(value), "option.min", "option.max"); err != nil {
+				return err
+			}
+			if fieldValue.OverflowInt(value) {
+				return fmt.Errorf("option %q overflows %s", name, field.Type)
+			}
+			fieldValue.SetInt(value)
+		case discordgo.ApplicationCommandOptionNumber:
+			value := option.FloatValue()
+			if err := checkOptionRange(field, name, value, "option.min", "option.max"); err != nil {
+				return err
+			}
+			fieldValue.SetFloat(value)
+		case discordgo.ApplicationCommandOptionBoolean:
+			fieldValue.SetBool(option.BoolValue())
+		case discordgo.ApplicationCommandOptionUser:
+			id := optionID(option)
+			user := resolved.Users[id]
+			if user == nil {
+				user = &discordgo.User{ID: id}
+			}
+			if field.Type == memberOptionType {
+				member := resolved.Members[id]
+				if member == nil {
+					return localizedError{"option.notMember", []any{id}}
+				}
+				member.User = user
+				fieldValue.Set(reflect.ValueOf(member))
+			} else {
+				fieldValue.Set(reflect.ValueOf(user))
+			}
+		case discordgo.ApplicationCommandOptionChannel:
+			id := optionID(option)
+			channel := resolved.Channels[id]
+			if channel == nil {
+				channel = &discordgo.Channel{ID: id}
+			}
+			fieldValue.Set(reflect.ValueOf(channel))
+		case discordgo.ApplicationCommandOptionRole:
+			id := optionID(option)
+			role := resolved.Roles[id]
+			if role == nil {
+				role = &discordgo.Role{ID: id}
+			}
+			fieldValue.Set(reflect.ValueOf(role))
+		case discordgo.ApplicationCommandOptionAttachment:
+			attachment := resolved.Attachments[optionID(option)]
+			if attachment == nil {
+				return localizedError{"option.attachment", []any{name}}
+			}
+			fieldValue.Set(reflect.ValueOf(attachment))
+		}
+	}
+	return nil
+}
+
+func checkOptionRange(field reflect.StructField, name string, value float64, minKey, maxKey string) error {
+	if minTag, ok := field.Tag.Lookup("min"); ok {
+		if minValue, err := strconv.ParseFloat(minTag, 64); err == nil && value < minValue {
+			return localizedError{minKey, []any{name, minTag}}
+		}
+	}
+	if maxTag, ok := field.Tag.Lookup("max"); ok {
+		if maxValue, err := strconv.ParseFloat(maxTag, 64); err == nil && value > maxValue {
+			return localizedError{maxKey, []any{name, maxTag}}
+		}
+	}
+	return nil
+}
+
+func setDefaultOption(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("defaults are not supported for %s", v.Type())
+	}
+	return nil
+}
+
+// optionID returns the snowflake held by a user, channel, role or attachment option
+func optionID(option *discordgo.ApplicationCommandInteractionDataOption) string {
+	if id, ok := option.Value.(string); ok {
+		return id
+	}
+	return fmt.Sprint(option.Value)
+}
+
+// focusedOption returns the option being typed in an autocomplete interaction
+func focusedOption(options []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, option := range options {
+		if option.Focused {
+			return option
+		}
+		if focused := focusedOption(option.Options); focused != nil {
+			return focused
+		}
+	}
+	return nil
+}
+
+// respondOptionError tells the user why their options were rejected
+func respondOptionError(s *discordgo.Session, i *discordgo.InteractionCreate, err error) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: trError(i, err),
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,270 @@
+package interactions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Snooze buttons on delivered reminders
+var SNOOZE_OPTIONS = []struct{
+	Minutes int
+	Label string
+}{
+	{10, "remind.snooze10m"},
+	{60, "remind.snooze1h"},
+	{24 * 60, "remind.snooze1d"},
+}
+
+type remindOptions struct {
+	When string `option:"when" description:"When to remind you, like in 2h30m, tomorrow 9am or fri 17:00" required:"true"`
+	Text string `option:"text" description:"What to remind you about" required:"true" max:"1500"`
+	Here bool `option:"here" description:"Ping you in this channel instead of sending a DM"`
+}
+
+// Reminder is what a reminder job reminds its user about
+type Reminder struct {
+	Text string `json:"text"`
+	// Jump link to the message the reminder is about
+	Link string `json:"link,omitempty"`
+}
+
+func messageLink(guildID, channelID, messageID string) string {
+	if guildID == "" {
+		guildID = "@me"
+	}
+	return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, messageID)
+}
+
+// scheduleReminder creates a reminder job for the user of an interaction and
+// tells them when it is. The response is deferred first since creating the DM
+// channel and storing the job can take longer than Discord waits.
+func scheduleReminder(s *discordgo.Session, i *discordgo.InteractionCreate, when string, reminder *Reminder, here bool) {
+	ctx := context.Background()
+	createdTime, err := discordgo.SnowflakeTimestamp(i.ID)
+	if err != nil {
+		log.Println("Error getting interaction time", err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	content := tr(i, "remind.scheduleFailed")
+	defer func() {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: &content,
+		})
+	}()
+	user := invokingUser(i)
+	loc := userLocation(ctx, user.ID, i.GuildID)
+	remindTime, err := parseTime(when, createdTime, loc)
+	if err != nil {
+		content = trError(i, err)
+		return
+	}
+	if !remindTime.After(createdTime) {
+		content = tr(i, "remind.past", remindTime.Unix())
+		return
+	}
+	job := &ScheduledJob{
+		Kind: "remind",
+		GuildID: i.GuildID,
+		UserID: user.ID,
+		Time: remindTime.UnixMilli(),
+		MissedPolicy: MISSED_LATE,
+		CreatedAt: createdTime.UnixMilli(),
+		Locale: interactionLocale(i),
+		Reminder: reminder,
+		ApplicationID: i.AppID,
+		InteractionToken: i.Token,
+	}
+	if here && i.GuildID != "" {
+		if err := checkSendTarget(s, i.ChannelID, user.ID, &ScheduledMessage{}); err != nil {
+			content = trError(i, err)
+			return
+		}
+		job.ChannelID = i.ChannelID
+	} else {
+		channel, err := s.UserChannelCreate(user.ID)
+		if err != nil {
+			log.Println("Error creating DM channel", err)
+			return
+		}
+		job.ChannelID = channel.ID
+		job.DM = true
+	}
+	if err := scheduleJob(ctx, job); err != nil {
+		log.Println("Error scheduling reminder", err)
+		return
+	}
+	content = tr(i, "remind.scheduled", remindTime.Unix(), remindTime.Unix()) + "\n" + tr(i, "time.local", formatLocalTime(remindTime, loc), loc.String())
+}
+
+// prepareReminder returns the function that delivers a reminder with buttons
+// to snooze it
+func prepareReminder(s *discordgo.Session, job *ScheduledJob) (func() (*discordgo.Message, error), error) {
+	if job.Reminder == nil {
+		return nil, fmt.Errorf("reminder job %s has no reminder", job.ID)
+	}
+	content := translate(job.Locale, "remind.message", job.UserID, job.Reminder.Text)
+	if job.Reminder.Link != "" {
+		content += "\n" + job.Reminder.Link
+	}
+	var buttons []discordgo.MessageComponent
+	for _, snooze := range SNOOZE_OPTIONS {
+		buttons = append(buttons, discordgo.Button{
+			Label: translate(job.Locale, snooze.Label),
+			Style: discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("remindSnooze:%s:%d", job.ID, snooze.Minutes),
+		})
+	}
+	send := &discordgo.MessageSend{
+		Content: content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{Users: []string{job.UserID}},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: buttons},
+		},
+	}
+	return func() (*discordgo.Message, error) {
+		return s.ChannelMessageSendComplex(job.ChannelID, send)
+	}, nil
+}
+
+func init() {
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
+		Name: "remind",
+		Description: "Remind you about something later",
+		Options: commandOptions(remindOptions{}),
+		IntegrationTypes: ALL_INTEGRATIONS,
+		Contexts: ALL_CONTEXTS,
+	}))
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
+		Name: "Remind me about this",
+		Type: discordgo.MessageApplicationCommand,
+		IntegrationTypes: ALL_INTEGRATIONS,
+		Contexts: ALL_CONTEXTS,
+	}))
+	CommandHandlers["remind"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		var options remindOptions
+		if err := parseOptions(i.ApplicationCommandData(), &options); err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+		scheduleReminder(s, i, options.When, &Reminder{Text: options.Text}, options.Here)
+	}
+	CommandHandlers["Remind me about this"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		data := i.ApplicationCommandData()
+		note := ""
+		if message := data.Resolved.Messages[data.TargetID]; message != nil {
+			note = message.Content
+			if runes := []rune(note); len(runes) > 200 {
+				note = string(runes[:199]) + "…"
+			}
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: fmt.Sprintf("remindModal:%s:%s", i.ChannelID, data.TargetID),
+				Title: tr(i, "remind.modalTitle"),
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID: "when",
+								Label: tr(i, "remind.modalWhen"),
+								Style: discordgo.TextInputShort,
+								Placeholder: "in 1h, tomorrow 9am, fri 17:00",
+								Required: true,
+								MaxLength: 100,
+							},
+						},
+					},
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID: "text",
+								Label: tr(i, "remind.modalText"),
+								Style: discordgo.TextInputParagraph,
+								Value: note,
+								MaxLength: 1500,
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	ModalHandlers["remindModal"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		data := i.ModalSubmitData()
+		args := strings.Split(data.CustomID, ":")
+		if len(args) != 3 {
+			return
+		}
+		text := modalValue(data, "text")
+		if text == "" {
+			text = tr(i, "remind.defaultText")
+		}
+		reminder := &Reminder{
+			Text: text,
+			Link: messageLink(i.GuildID, args[1], args[2]),
+		}
+		scheduleReminder(s, i, modalValue(data, "when"), reminder, false)
+	}
+	ComponentHandlers["remindSnooze"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		args := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(args) != 3 {
+			return
+		}
+		minutes, err := strconv.Atoi(args[2])
+		if err != nil {
+			return
+		}
+		ctx := context.Background()
+		job, err := getFinishedJob(ctx, args[1])
+		if err != nil {
+			log.Println("Error getting reminder", err)
+			return
+		}
+		if job == nil || job.Reminder == nil || job.UserID != invokingUser(i).ID {
+			respondOptionError(s, i, localizedError{"remind.notYours", nil})
+			return
+		}
+		createdTime, err := discordgo.SnowflakeTimestamp(i.ID)
+		if err != nil {
+			log.Println("Error getting interaction time", err)
+			return
+		}
+		snoozed := &ScheduledJob{
+			Kind: "remind",
+			GuildID: job.GuildID,
+			ChannelID: job.ChannelID,
+			DM: job.DM,
+			UserID: job.UserID,
+			Time: createdTime.Add(time.Duration(minutes) * time.Minute).UnixMilli(),
+			MissedPolicy: MISSED_LATE,
+			CreatedAt: createdTime.UnixMilli(),
+			Locale: job.Locale,
+			Reminder: job.Reminder,
+		}
+		if err := scheduleJob(ctx, snoozed); err != nil {
+			log.Println("Error snoozing reminder", err)
+			respondOptionError(s, i, localizedError{"remind.scheduleFailed", nil})
+			return
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content: i.Message.Content + "\n-# " + tr(i, "remind.snoozed", snoozed.Time / 1000),
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+	}
+	jobRunners["remind"] = prepareReminder
+}
@@ -0,0 +1,592 @@
+package interactions
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"firebase.google.com/go/v4/db"
+	"github.com/bwmarrin/discordgo"
+	"github.com/anishmit/gobot/firebase"
+)
+
+// The leader reloads every job this often in case it missed a change
+const SCHEDULER_POLL_INTERVAL = 15 * time.Second
+// Other processes signal the leader when they add or change a job, which it
+// checks for this often
+const SCHEDULER_SIGNAL_INTERVAL = time.Second
+// A job that is overdue by more than this when it fires was missed while the
+// bot was offline, and its missed policy decides what happens to it
+const MISSED_JOB_GRACE = time.Minute
+// A job claimed by a process that died before finishing it is retried
+const STALE_JOB_CLAIM = time.Minute
+// Interaction tokens can be used for followup messages for 15 minutes
+const INTERACTION_TOKEN_LIFETIME = 14 * time.Minute
+
+const (
+	JOB_PENDING = "pending"
+	JOB_RUNNING = "running"
+	JOB_PAUSED = "paused"
+	JOB_SENT = "sent"
+	JOB_SKIPPED = "skipped"
+	JOB_FAILED = "failed"
+	JOB_CANCELLED = "cancelled"
+)
+
+const (
+	MISSED_LATE = "late"
+	MISSED_SKIP = "skip"
+)
+
+// ScheduledJob is stored under scheduledJobs/<id> until it fires, and is then
+// moved to scheduledJobHistory/<id> with its outcome. Recurring jobs stay
+// under scheduledJobs with their next time until their recurrence ends.
+type ScheduledJob struct {
+	ID string `json:"-"`
+	Kind string `json:"kind"`
+	GuildID string `json:"guildId,omitempty"`
+	ChannelID string `json:"channelId"`
+	// Whether ChannelID is the DM channel of the user
+	DM bool `json:"dm,omitempty"`
+	UserID string `json:"userId"`
+	Time int64 `json:"time"`
+	MissedPolicy string `json:"missedPolicy"`
+	Status string `json:"status"`
+	ClaimedAt int64 `json:"claimedAt,omitempty"`
+	Error string `json:"error,omitempty"`
+	CreatedAt int64 `json:"createdAt"`
+	Locale discordgo.Locale `json:"locale,omitempty"`
+	Message *ScheduledMessage `json:"message,omitempty"`
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+	Reminder *Reminder `json:"reminder,omitempty"`
+	// Milliseconds between the time and when the message was created
+	Offset int64 `json:"offset,omitempty"`
+	// Interaction that created the job, used to report its outcome while the
+	// token is still valid
+	ApplicationID string `json:"applicationId,omitempty"`
+	InteractionToken string `json:"interactionToken,omitempty"`
+}
+
+// Functions that prepare jobs before their time, keyed by job kind. They
+// return the function that carries out the job, which returns the message it
+// created if any.
+var jobRunners = map[string]func(s *discordgo.Session, job *ScheduledJob) (func() (*discordgo.Message, error), error){}
+
+// errJobObsolete is returned by a runner when what the job was for is gone.
+// The job ends as cancelled without being reported or rescheduled.
+var errJobObsolete = errors.New("job is obsolete")
+
+type jobQueue []*ScheduledJob
+
+func (q jobQueue) Len() int { return len(q) }
+func (q jobQueue) Less(i, j int) bool { return q[i].Time < q[j].Time }
+func (q jobQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *jobQueue) Push(x any) { *q = append(*q, x.(*ScheduledJob)) }
+func (q *jobQueue) Pop() any {
+	old := *q
+	job := old[len(old) - 1]
+	*q = old[:len(old) - 1]
+	return job
+}
+
+// scheduler fires jobs from a single timer loop in the process holding the
+// scheduler leader lock
+type scheduler struct {
+	mutex sync.Mutex
+	running bool
+	queue jobQueue
+	// Jobs currently in the queue by ID. Removed jobs stay in the queue until
+	// they reach the front and are skipped because they aren't in queued.
+	queued map[string]*ScheduledJob
+	wake chan struct{}
+}
+
+var jobScheduler = &scheduler{
+	queued: map[string]*ScheduledJob{},
+	wake: make(chan struct{}, 1),
+}
+var scheduledJobsRef = firebase.DB.NewRef("scheduledJobs")
+var scheduledJobHistoryRef = firebase.DB.NewRef("scheduledJobHistory")
+// Changed by processes other than the leader to have it reload the jobs
+var schedulerSignalRef = firebase.DB.NewRef("schedulerSignal")
+
+// add queues a job if this process runs the scheduler, and otherwise signals
+// the process that does
+func (sch *scheduler) add(job *ScheduledJob) {
+	sch.mutex.Lock()
+	defer sch.mutex.Unlock()
+	if !sch.running {
+		go signalScheduler()
+		return
+	}
+	sch.queued[job.ID] = job
+	heap.Push(&sch.queue, job)
+	select {
+	case sch.wake <- struct{}{}:
+	default:
+	}
+}
+
+// remove takes a job out of the queue, or signals the process that runs the
+// scheduler
+func (sch *scheduler) remove(id string) {
+	sch.mutex.Lock()
+	defer sch.mutex.Unlock()
+	if !sch.running {
+		go signalScheduler()
+		return
+	}
+	delete(sch.queued, id)
+}
+
+// next returns the earliest queued job
+func (sch *scheduler) next() *ScheduledJob {
+	sch.mutex.Lock()
+	defer sch.mutex.Unlock()
+	for len(sch.queue) > 0 {
+		job := sch.queue[0]
+		if sch.queued[job.ID] == job {
+			return job
+		}
+		heap.Pop(&sch.queue)
+	}
+	return nil
+}
+
+// popDue removes and returns every queued job due by t
+func (sch *scheduler) popDue(t time.Time) []*ScheduledJob {
+	sch.mutex.Lock()
+	defer sch.mutex.Unlock()
+	var due []*ScheduledJob
+	for len(sch.queue) > 0 && sch.queue[0].Time <= t.UnixMilli() {
+		job := heap.Pop(&sch.queue).(*ScheduledJob)
+		if sch.queued[job.ID] == job {
+			delete(sch.queued, job.ID)
+			due = append(due, job)
+		}
+	}
+	return due
+}
+
+// load queues stored jobs that aren't queued yet
+func (sch *scheduler) load(ctx context.Context) {
+	jobs, err := listJobs(ctx)
+	if err != nil {
+		log.Println("Error loading scheduled jobs", err)
+		return
+	}
+	staleClaim := time.Now().Add(-STALE_JOB_CLAIM).UnixMilli()
+	for _, job := range jobs {
+		id := job.ID
+		if job.Status == JOB_PAUSED {
+			sch.remove(id)
+			continue
+		}
+		if job.Status == JOB_RUNNING && job.ClaimedAt > staleClaim {
+			continue
+		}
+		// Jobs rescheduled by another process are queued again at their new time
+		sch.mutex.Lock()
+		queued, ok := sch.queued[id]
+		sch.mutex.Unlock()
+		if !ok || queued.Time != job.Time {
+			sch.add(job)
+		}
+	}
+}
+
+// signalScheduler has the leader reload the jobs soon
+func signalScheduler() {
+	if err := schedulerSignalRef.Set(context.Background(), time.Now().UnixNano()); err != nil {
+		log.Println("Error signalling scheduler", err)
+	}
+}
+
+// signalled reports whether the scheduler signal changed since last
+func signalled(ctx context.Context, last *int64) bool {
+	var signal int64
+	if err := schedulerSignalRef.Get(ctx, &signal); err != nil {
+		log.Println("Error reading scheduler signal", err)
+		return false
+	}
+	changed := signal != *last
+	*last = signal
+	return changed
+}
+
+// run is the scheduler's leader job
+func (sch *scheduler) run(ctx context.Context, s *discordgo.Session) {
+	sch.mutex.Lock()
+	sch.running = true
+	sch.queue = nil
+	sch.queued = map[string]*ScheduledJob{}
+	sch.mutex.Unlock()
+	defer func() {
+		sch.mutex.Lock()
+		sch.running = false
+		sch.mutex.Unlock()
+	}()
+
+	calibration.load(ctx)
+	var lastSignal int64
+	signalled(ctx, &lastSignal)
+	sch.load(ctx)
+	poll := time.NewTicker(SCHEDULER_POLL_INTERVAL)
+	defer poll.Stop()
+	signal := time.NewTicker(SCHEDULER_SIGNAL_INTERVAL)
+	defer signal.Stop()
+	for {
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		if job := sch.next(); job != nil {
+			timer = time.NewTimer(time.Until(time.UnixMilli(job.Time).Add(-JOB_WAKE_LEAD)))
+			timerC = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-sch.wake:
+		case <-poll.C:
+			sch.load(ctx)
+		case <-signal.C:
+			if signalled(ctx, &lastSignal) {
+				sch.load(ctx)
+			}
+		case <-timerC:
+			for _, job := range sch.popDue(time.Now().Add(JOB_WAKE_LEAD)) {
+				// Claimed jobs finish even if leadership is lost meanwhile
+				go fireJob(context.Background(), s, job)
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// scheduleJob stores a new job and queues it
+func scheduleJob(ctx context.Context, job *ScheduledJob) error {
+	job.Status = JOB_PENDING
+	ref, err := scheduledJobsRef.Push(ctx, job)
+	if err != nil {
+		return err
+	}
+	job.ID = ref.Key
+	jobScheduler.add(job)
+	return nil
+}
+
+// claimJob marks a pending job as running so that it only fires once, and
+// returns its stored state. Jobs that were rescheduled since they were queued
+// aren't claimed.
+func claimJob(ctx context.Context, id string, jobTime int64) (*ScheduledJob, error) {
+	var claimed *ScheduledJob
+	err := scheduledJobsRef.Child(id).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		claimed = nil
+		var job *ScheduledJob
+		if err := value.Unmarshal(&job); err != nil {
+			return nil, err
+		}
+		if job == nil || job.Time != jobTime {
+			return job, nil
+		}
+		now := time.Now().UnixMilli()
+		if job.Status == JOB_PENDING || (job.Status == JOB_RUNNING && job.ClaimedAt < now - STALE_JOB_CLAIM.Milliseconds()) {
+			job.Status = JOB_RUNNING
+			job.ClaimedAt = now
+			claimed = job
+		}
+		return job, nil
+	})
+	if err != nil || claimed == nil {
+		return nil, err
+	}
+	claimed.ID = id
+	return claimed, nil
+}
+
+// finishJob moves a job to the history with its outcome
+func finishJob(ctx context.Context, job *ScheduledJob) error {
+	if err := scheduledJobHistoryRef.Child(job.ID).Set(ctx, job); err != nil {
+		return err
+	}
+	deleteScheduledAttachments(ctx, job)
+	return scheduledJobsRef.Child(job.ID).Delete(ctx)
+}
+
+func fireJob(ctx context.Context, s *discordgo.Session, queued *ScheduledJob) {
+	job, err := claimJob(ctx, queued.ID, queued.Time)
+	if err != nil {
+		log.Println("Error claiming scheduled job", queued.ID, err)
+		return
+	}
+	if job == nil {
+		return
+	}
+	obsolete := false
+	if time.Now().UnixMilli() - job.Time > MISSED_JOB_GRACE.Milliseconds() && job.MissedPolicy == MISSED_SKIP {
+		job.Status = JOB_SKIPPED
+	} else if runner, ok := jobRunners[job.Kind]; !ok {
+		job.Status = JOB_FAILED
+		job.Error = "unknown job kind " + job.Kind
+	} else if err := deliverJob(ctx, s, job, runner); errors.Is(err, errJobObsolete) {
+		job.Status = JOB_CANCELLED
+		obsolete = true
+	} else if err != nil {
+		job.Status = JOB_FAILED
+		job.Error = describeDeliveryError(job.Locale, err)
+	} else {
+		job.Status = JOB_SENT
+	}
+	if !obsolete {
+		reportJobOutcome(s, job)
+	}
+	if job.Recurrence != nil && !obsolete {
+		rescheduled, err := rescheduleJob(ctx, job)
+		if err != nil {
+			log.Println("Error rescheduling job", job.ID, err)
+		}
+		if rescheduled {
+			return
+		}
+	}
+	if err := finishJob(ctx, job); err != nil {
+		log.Println("Error finishing scheduled job", job.ID, err)
+	}
+}
+
+// deliverJob prepares a job, waits until it has to be sent for its message to
+// be created at its time and carries it out, recording how close it was.
+// Transient failures are retried, after checking that a failure that may have
+// come after the message was created didn't create it.
+func deliverJob(ctx context.Context, s *discordgo.Session, job *ScheduledJob, runner func(s *discordgo.Session, job *ScheduledJob) (func() (*discordgo.Message, error), error)) error {
+	send, err := runner(s, job)
+	if err != nil {
+		return err
+	}
+	warmConnection(s)
+	target := time.UnixMilli(job.Time)
+	waitUntil(target.Add(-calibration.lead(s)))
+	var message *discordgo.Message
+	var first, start, end time.Time
+	recovered := false
+	delay := DELIVERY_RETRY_DELAY
+	for attempt := 1; ; attempt++ {
+		start = time.Now()
+		if attempt == 1 {
+			first = start
+		}
+		message, err = send()
+		end = time.Now()
+		if err == nil || isPermanentDeliveryError(err) {
+			break
+		}
+		log.Printf("Error delivering job %s, attempt %d: %v", job.ID, attempt, err)
+		if !isUnsentDeliveryError(err) {
+			found, lookupErr := findDeliveredMessage(s, job.ChannelID, first)
+			if lookupErr != nil {
+				// Sending again could post the message twice
+				log.Println("Error looking for delivered message", lookupErr)
+				break
+			}
+			if found != nil {
+				message, err, recovered = found, nil, true
+				break
+			}
+		}
+		if attempt == DELIVERY_ATTEMPTS {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	if err != nil {
+		return err
+	}
+	if recovered {
+		// The request's timing is unknown, so it can't calibrate
+		if created, err := discordgo.SnowflakeTimestamp(message.ID); err == nil {
+			job.Offset = created.Sub(target).Milliseconds()
+		}
+	} else if message != nil {
+		offset, err := calibration.record(ctx, message, target, start, end)
+		if err != nil {
+			log.Println("Error recording delivery offset", err)
+		}
+		job.Offset = offset.Milliseconds()
+	}
+	return nil
+}
+
+// rescheduleJob sets a recurring job that just fired to its next time, and
+// returns false if its recurrence has ended. Occurrences missed while the bot
+// was offline only fire once.
+func rescheduleJob(ctx context.Context, job *ScheduledJob) (bool, error) {
+	job.Recurrence.Occurrences++
+	next, ok := job.Recurrence.next(time.UnixMilli(max(time.Now().UnixMilli(), job.Time)))
+	if !ok {
+		return false, nil
+	}
+	job.Time = next.UnixMilli()
+	job.Status = JOB_PENDING
+	job.ClaimedAt = 0
+	job.Error = ""
+	if err := scheduledJobsRef.Child(job.ID).Set(ctx, job); err != nil {
+		return false, err
+	}
+	jobScheduler.add(job)
+	return true, nil
+}
+
+// getJob returns a stored job, or nil if it doesn't exist
+func getJob(ctx context.Context, id string) (*ScheduledJob, error) {
+	var job *ScheduledJob
+	if err := scheduledJobsRef.Child(id).Get(ctx, &job); err != nil {
+		return nil, err
+	}
+	if job != nil {
+		job.ID = id
+	}
+	return job, nil
+}
+
+// listJobs returns every stored job ordered by time
+func listJobs(ctx context.Context) ([]*ScheduledJob, error) {
+	var stored map[string]*ScheduledJob
+	if err := scheduledJobsRef.Get(ctx, &stored); err != nil {
+		return nil, err
+	}
+	jobs := make([]*ScheduledJob, 0, len(stored))
+	for id, job := range stored {
+		job.ID = id
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Time < jobs[j].Time
+	})
+	return jobs, nil
+}
+
+// updateJob changes a pending or paused job, returning nil if the job doesn't
+// exist, is firing or update returns false. The scheduler picks up the new
+// time or status.
+func updateJob(ctx context.Context, id string, update func(job *ScheduledJob) bool) (*ScheduledJob, error) {
+	var updated *ScheduledJob
+	err := scheduledJobsRef.Child(id).Transaction(ctx, func(value db.TransactionNode) (interface{}, error) {
+		updated = nil
+		var job *ScheduledJob
+		if err := value.Unmarshal(&job); err != nil {
+			return nil, err
+		}
+		if job == nil {
+			return nil, nil
+		}
+		if (job.Status == JOB_PENDING || job.Status == JOB_PAUSED) && update(job) {
+			updated = job
+		}
+		return job, nil
+	})
+	if err != nil || updated == nil {
+		return nil, err
+	}
+	updated.ID = id
+	if updated.Status == JOB_PENDING {
+		jobScheduler.add(updated)
+	} else {
+		jobScheduler.remove(id)
+	}
+	return updated, nil
+}
+
+// getFinishedJob returns a job from the history, or nil if it isn't there
+func getFinishedJob(ctx context.Context, id string) (*ScheduledJob, error) {
+	var job *ScheduledJob
+	if err := scheduledJobHistoryRef.Child(id).Get(ctx, &job); err != nil {
+		return nil, err
+	}
+	if job != nil {
+		job.ID = id
+	}
+	return job, nil
+}
+
+// setJobPaused pauses a pending job or resumes a paused one, returning nil if
+// the job wasn't in the right state. Resumed recurring jobs continue from
+// their next occurrence.
+func setJobPaused(ctx context.Context, id string, paused bool) (*ScheduledJob, error) {
+	return updateJob(ctx, id, func(job *ScheduledJob) bool {
+		if paused && job.Status == JOB_PENDING {
+			job.Status = JOB_PAUSED
+			return true
+		}
+		if !paused && job.Status == JOB_PAUSED {
+			job.Status = JOB_PENDING
+			now := time.Now()
+			if job.Recurrence != nil && job.Time < now.UnixMilli() {
+				if next, ok := job.Recurrence.next(now); ok {
+					job.Time = next.UnixMilli()
+				}
+			}
+			return true
+		}
+		return false
+	})
+}
+
+// cancelJob moves a pending or paused job to the history without firing it
+func cancelJob(ctx context.Context, id string) (*ScheduledJob, error) {
+	job, err := updateJob(ctx, id, func(job *ScheduledJob) bool {
+		job.Status = JOB_CANCELLED
+		return true
+	})
+	if err != nil || job == nil {
+		return nil, err
+	}
+	return job, finishJob(ctx, job)
+}
+
+// reportJobOutcome tells the user who scheduled a job what happened to it,
+// with a followup while the interaction token is valid and a DM otherwise.
+// Successful jobs are visible on their own, so they aren't DMed.
+func reportJobOutcome(s *discordgo.Session, job *ScheduledJob) {
+	args := []any{job.Time / 1000, job.ChannelID}
+	if job.Status == JOB_SENT {
+		args = append(args, job.Offset)
+	} else if job.Status == JOB_FAILED {
+		args = append(args, job.Error)
+	}
+	content := translate(job.Locale, "job." + job.Kind + "." + job.Status, args...)
+	if job.InteractionToken != "" && time.Since(time.UnixMilli(job.CreatedAt)) < INTERACTION_TOKEN_LIFETIME {
+		_, err := s.FollowupMessageCreate(&discordgo.Interaction{
+			AppID: job.ApplicationID,
+			Token: job.InteractionToken,
+		}, false, &discordgo.WebhookParams{
+			Content: content,
+			Flags: discordgo.MessageFlagsEphemeral,
+		})
+		if err == nil {
+			return
+		}
+		log.Println("Error sending job outcome followup", err)
+	}
+	if job.Status == JOB_SENT {
+		return
+	}
+	channel, err := s.UserChannelCreate(job.UserID)
+	if err != nil {
+		log.Println("Error creating DM channel", err)
+		return
+	}
+	if _, err := s.ChannelMessageSend(channel.ID, content); err != nil {
+		log.Println("Error sending job outcome DM", err)
+	}
+}
+
+func init() {
+	LeaderJobs["scheduler"] = jobScheduler.run
+}
@@ -2,61 +2,851 @@ package interactions
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
-	"os"
 	"github.com/bwmarrin/discordgo"
+	"github.com/anishmit/gobot/firebase"
 )
 
-var (
-	REQUEST_BODY = map[string]string{
-		"content": "Scheduled message sent.",
+// Content of jobs scheduled before messages could be customized
+const DEFAULT_SCHEDULED_CONTENT = "Scheduled message sent."
+const SEND_LIST_PAGE_SIZE = 10
+// Length of message previews in lists and autocomplete choices
+const SEND_PREVIEW_LENGTH = 50
+
+type sendOptions struct {
+	Schedule *sendScheduleOptions `option:"schedule" description:"Schedule sending a message"`
+	List *sendListOptions `option:"list" description:"List scheduled messages"`
+	Edit *sendEditOptions `option:"edit" description:"Change the time or content of a scheduled message"`
+	Cancel *sendJobOptions `option:"cancel" description:"Cancel a scheduled message"`
+	Pause *sendJobOptions `option:"pause" description:"Pause a scheduled message"`
+	Resume *sendJobOptions `option:"resume" description:"Resume a paused scheduled message"`
+}
+
+type sendJobOptions struct {
+	ID string `option:"id" description:"ID of the scheduled message" required:"true" autocomplete:"true"`
+}
+
+type sendListOptions struct {
+	All bool `option:"all" description:"List everyone's scheduled messages in this server, for server managers"`
+	Page int `option:"page" description:"Page to show" min:"1" default:"1"`
+}
+
+type sendEditOptions struct {
+	ID string `option:"id" description:"ID of the scheduled message" required:"true" autocomplete:"true"`
+	Time string `option:"time" description:"New time, like in 2h30m, tomorrow 9am, fri 17:00 or Unix time in ms"`
+	Content string `option:"content" description:"New message content" max:"2000"`
+}
+
+type sendScheduleOptions struct {
+	Time string `option:"time" description:"When to send, like in 2h30m, tomorrow 9am, fri 17:00 or Unix time in ms"`
+	Repeat string `option:"repeat" description:"Repeat like daily 09:00, weekly mon,fri 17:00 or a cron expression"`
+	Until string `option:"until" description:"Stop repeating after this time"`
+	Count int `option:"count" description:"Stop repeating after this many messages" min:"1"`
+	Channel *discordgo.Channel `option:"channel" description:"Channel, thread or forum post to send in, this one by default" channels:"text,news,newsThread,publicThread,privateThread"`
+	DM bool `option:"dm" description:"Send it to you in DMs instead"`
+	Content string `option:"content" description:"Message content, asked for in a form if nothing else is given" max:"2000"`
+	Title string `option:"title" description:"Embed title" max:"256"`
+	Description string `option:"description" description:"Embed description" max:"4096"`
+	Color string `option:"color" description:"Embed color as a hex code like #ff8000"`
+	File *discordgo.MessageAttachment `option:"file" description:"File to attach"`
+	Mentions string `option:"mentions" description:"Mentions that may ping" choices:"none,users,roles,everyone" default:"users"`
+	Missed string `option:"missed" description:"What to do if the bot was offline at that time" choices:"late,skip" default:"late"`
+}
+
+// ScheduledMessage is the message a send job posts
+type ScheduledMessage struct {
+	Content string `json:"content,omitempty"`
+	Embed *ScheduledEmbed `json:"embed,omitempty"`
+	Attachments []ScheduledAttachment `json:"attachments,omitempty"`
+	AllowedMentions string `json:"allowedMentions,omitempty"`
+}
+
+type ScheduledEmbed struct {
+	Title string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Color int `json:"color,omitempty"`
+}
+
+// ScheduledAttachment is a file kept in storage until its message is sent,
+// since attachment URLs from interactions expire
+type ScheduledAttachment struct {
+	Name string `json:"name"`
+	ContentType string `json:"contentType,omitempty"`
+	Object string `json:"object"`
+}
+
+// Sends waiting for their content to be entered in a modal or for the user to
+// confirm the time
+type pendingSend struct {
+	job *ScheduledJob
+	file *discordgo.MessageAttachment
+	created time.Time
+}
+
+// Pending sends keyed by the ID of the interaction that started them
+var pendingSends = map[string]*pendingSend{}
+var pendingSendsMutex sync.Mutex
+
+func addPendingSend(id string, pending *pendingSend) {
+	pendingSendsMutex.Lock()
+	defer pendingSendsMutex.Unlock()
+	for pendingID, pending := range pendingSends {
+		if time.Since(pending.created) > INTERACTION_TOKEN_LIFETIME {
+			delete(pendingSends, pendingID)
+		}
 	}
-	AUTHORIZATION_HEADER = fmt.Sprintf("Bot %s", os.Getenv("BOT_TOKEN"))
-	jsonBody []byte
-)
+	pendingSends[id] = pending
+}
 
-func sendScheduledMessage(channelID string) {
-	if req, err := http.NewRequest("POST", fmt.Sprintf("https://discord.com/api/channels/%s/messages", channelID), bytes.NewBuffer(jsonBody)); err != nil {
-		log.Println("Could not create new HTTP request", err)
-	} else {
-		req.Header.Add("Authorization", AUTHORIZATION_HEADER)
-		req.Header.Add("Content-Type", "application/json")
-		http.DefaultClient.Do(req)
+func takePendingSend(id string) *pendingSend {
+	pendingSendsMutex.Lock()
+	defer pendingSendsMutex.Unlock()
+	pending := pendingSends[id]
+	delete(pendingSends, id)
+	return pending
+}
+
+func parseColor(color string) (int, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(color, "#"), 16, 24)
+	if err != nil {
+		return 0, localizedError{"send.invalidColor", []any{color}}
 	}
+	return int(value), nil
 }
 
-func init() {
-	var err error
-	jsonBody, err = json.Marshal(REQUEST_BODY)
+func allowedMentions(mentions string) *discordgo.MessageAllowedMentions {
+	allowed := &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+	switch mentions {
+	case "everyone":
+		allowed.Parse = append(allowed.Parse, discordgo.AllowedMentionTypeEveryone)
+		fallthrough
+	case "roles":
+		allowed.Parse = append(allowed.Parse, discordgo.AllowedMentionTypeRoles)
+		fallthrough
+	case "users", "":
+		allowed.Parse = append(allowed.Parse, discordgo.AllowedMentionTypeUsers)
+	}
+	return allowed
+}
+
+// storeAttachment copies an attachment into storage
+func storeAttachment(ctx context.Context, attachment *discordgo.MessageAttachment, object string) (ScheduledAttachment, error) {
+	res, err := http.Get(attachment.URL)
 	if err != nil {
-		log.Fatalln("Request body could not be JSON encoded", err)
+		return ScheduledAttachment{}, err
 	}
-	Commands = append(Commands, &discordgo.ApplicationCommand{
-		Name:        "send",
-		Description: "Schedule sending a message at a Unix epoch time in milliseconds",
-		Options: []*discordgo.ApplicationCommandOption{
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return ScheduledAttachment{}, fmt.Errorf("downloading attachment: %s", res.Status)
+	}
+	writer := firebase.Bucket.Object(object).NewWriter(ctx)
+	writer.ContentType = attachment.ContentType
+	if _, err := io.Copy(writer, res.Body); err != nil {
+		writer.Close()
+		return ScheduledAttachment{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return ScheduledAttachment{}, err
+	}
+	return ScheduledAttachment{
+		Name: attachment.Filename,
+		ContentType: attachment.ContentType,
+		Object: object,
+	}, nil
+}
+
+// deleteScheduledAttachments removes the stored files of a finished job
+func deleteScheduledAttachments(ctx context.Context, job *ScheduledJob) {
+	if job.Message == nil || firebase.Bucket == nil {
+		return
+	}
+	for _, attachment := range job.Message.Attachments {
+		if err := firebase.Bucket.Object(attachment.Object).Delete(ctx); err != nil {
+			log.Println("Error deleting scheduled attachment", attachment.Object, err)
+		}
+	}
+}
+
+// checkSendTarget checks that both the bot and a user can send a message in a
+// channel or thread
+func checkSendTarget(s *discordgo.Session, channelID, userID string, message *ScheduledMessage) error {
+	channel, err := s.State.Channel(channelID)
+	if err != nil {
+		if channel, err = s.Channel(channelID); err != nil {
+			if isPermanentDeliveryError(err) {
+				return localizedError{"send.unknownChannel", []any{channelID}}
+			}
+			return err
+		}
+	}
+	permissionChannelID := channelID
+	required := int64(discordgo.PermissionViewChannel | discordgo.PermissionSendMessages)
+	if channel.IsThread() {
+		// Threads use the permissions of their parent channel
+		if channel.ThreadMetadata != nil && channel.ThreadMetadata.Locked {
+			return localizedError{"send.threadLocked", []any{channelID}}
+		}
+		permissionChannelID = channel.ParentID
+		required = discordgo.PermissionViewChannel | discordgo.PermissionSendMessagesInThreads
+	}
+	if message.Embed != nil {
+		required |= discordgo.PermissionEmbedLinks
+	}
+	if len(message.Attachments) > 0 {
+		required |= discordgo.PermissionAttachFiles
+	}
+	for _, check := range []struct{
+		userID string
+		key string
+	}{
+		{s.State.User.ID, "send.botCannotSend"},
+		{userID, "send.userCannotSend"},
+	} {
+		permissions, err := s.UserChannelPermissions(check.userID, permissionChannelID)
+		if err != nil && !isPermanentDeliveryError(err) {
+			return err
+		}
+		if err != nil || permissions & required != required {
+			return localizedError{check.key, []any{channelID}}
+		}
+	}
+	return nil
+}
+
+// prepareScheduledMessage reads the stored files of a send job and returns
+// the function that posts it, which can be called again to retry
+func prepareScheduledMessage(s *discordgo.Session, job *ScheduledJob) (func() (*discordgo.Message, error), error) {
+	message := job.Message
+	if message == nil {
+		message = &ScheduledMessage{Content: DEFAULT_SCHEDULED_CONTENT}
+	}
+	// Permissions may have changed since the job was scheduled. A check that
+	// fails for a transient reason is repeated when sending, where failures
+	// are retried.
+	checked := job.DM
+	if !checked {
+		err := checkSendTarget(s, job.ChannelID, job.UserID, message)
+		if err != nil && isPermanentDeliveryError(err) {
+			return nil, err
+		}
+		checked = err == nil
+	}
+	send := &discordgo.MessageSend{
+		Content: message.Content,
+		AllowedMentions: allowedMentions(message.AllowedMentions),
+	}
+	if message.Embed != nil {
+		send.Embeds = []*discordgo.MessageEmbed{
 			{
-				Type: discordgo.ApplicationCommandOptionInteger,
-				Name: "time",
-				Description: "Unix epoch time in milliseconds",
-				Required: true,
+				Title: message.Embed.Title,
+				Description: message.Embed.Description,
+				Color: message.Embed.Color,
+			},
+		}
+	}
+	// Storage can be turned off after a job with files was created
+	if len(message.Attachments) > 0 && firebase.Bucket == nil {
+		return nil, localizedError{"send.noStorage", nil}
+	}
+	ctx := context.Background()
+	files := make([][]byte, len(message.Attachments))
+	for index, attachment := range message.Attachments {
+		reader, err := firebase.Bucket.Object(attachment.Object).NewReader(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("reading attachment %s: %w", attachment.Name, err)
+		}
+		files[index], err = io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading attachment %s: %w", attachment.Name, err)
+		}
+	}
+	return func() (*discordgo.Message, error) {
+		if !checked {
+			if err := checkSendTarget(s, job.ChannelID, job.UserID, message); err != nil {
+				return nil, err
+			}
+			checked = true
+		}
+		send.Files = nil
+		for index, attachment := range message.Attachments {
+			send.Files = append(send.Files, &discordgo.File{
+				Name: attachment.Name,
+				ContentType: attachment.ContentType,
+				Reader: bytes.NewReader(files[index]),
+			})
+		}
+		return s.ChannelMessageSendComplex(job.ChannelID, send)
+	}, nil
+}
+
+// confirmSend shows the resolved time of a send and asks the user to confirm it
+func confirmSend(s *discordgo.Session, i *discordgo.InteractionCreate, id string, pending *pendingSend) {
+	addPendingSend(id, pending)
+	seconds := pending.job.Time / 1000
+	content := tr(i, "send.confirm", seconds, seconds)
+	if recurrence := pending.job.Recurrence; recurrence != nil {
+		content = tr(i, "send.confirmRepeat", seconds, seconds, recurrence.Cron, recurrence.Timezone)
+	}
+	loc := userLocation(context.Background(), invokingUser(i).ID, i.GuildID)
+	content += "\n" + tr(i, "time.local", formatLocalTime(time.UnixMilli(pending.job.Time), loc), loc.String())
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags: discordgo.MessageFlagsEphemeral,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label: tr(i, "send.confirmButton"),
+							Style: discordgo.SuccessButton,
+							CustomID: "sendConfirm:" + id,
+						},
+						discordgo.Button{
+							Label: tr(i, "send.cancelButton"),
+							Style: discordgo.SecondaryButton,
+							CustomID: "sendCancel:" + id,
+						},
+					},
+				},
 			},
 		},
 	})
+}
+
+// completeSend stores the job's attachment and schedules it, answering the
+// confirmation button
+func completeSend(s *discordgo.Session, i *discordgo.InteractionCreate, job *ScheduledJob, file *discordgo.MessageAttachment) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	followup := func(content string) {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: &content,
+			Components: &[]discordgo.MessageComponent{},
+		})
+	}
+	ctx := context.Background()
+	job.ApplicationID = i.AppID
+	job.InteractionToken = i.Token
+	if file != nil {
+		attachment, err := storeAttachment(ctx, file, fmt.Sprintf("scheduledAttachments/%s/%s", i.ID, file.Filename))
+		if err != nil {
+			log.Println("Error storing scheduled attachment", err)
+			followup(tr(i, "send.scheduleFailed"))
+			return
+		}
+		job.Message.Attachments = append(job.Message.Attachments, attachment)
+	}
+	if err := scheduleJob(ctx, job); err != nil {
+		log.Println("Error scheduling message", err)
+		deleteScheduledAttachments(ctx, job)
+		followup(tr(i, "send.scheduleFailed"))
+		return
+	}
+	followup(tr(i, "send.scheduled", job.ID, job.Time / 1000, job.Time / 1000))
+}
+
+// isGuildManager reports whether the user of an interaction can manage the
+// server it was used in
+func isGuildManager(i *discordgo.InteractionCreate) bool {
+	return i.Member != nil && i.Member.Permissions & discordgo.PermissionManageGuild != 0
+}
+
+// canManageJob reports whether the user of an interaction may change a job,
+// which its creator and server managers can
+func canManageJob(i *discordgo.InteractionCreate, job *ScheduledJob) bool {
+	return job.UserID == invokingUser(i).ID || (i.GuildID == job.GuildID && isGuildManager(i))
+}
+
+// jobPreview returns the start of what a send job posts
+func jobPreview(job *ScheduledJob) string {
+	var preview string
+	if message := job.Message; message == nil {
+		preview = DEFAULT_SCHEDULED_CONTENT
+	} else if message.Content != "" {
+		preview = message.Content
+	} else if message.Embed != nil && message.Embed.Title != "" {
+		preview = message.Embed.Title
+	} else if message.Embed != nil {
+		preview = message.Embed.Description
+	} else if len(message.Attachments) > 0 {
+		preview = message.Attachments[0].Name
+	}
+	preview = strings.Join(strings.Fields(preview), " ")
+	if runes := []rune(preview); len(runes) > SEND_PREVIEW_LENGTH {
+		preview = string(runes[:SEND_PREVIEW_LENGTH - 1]) + "…"
+	}
+	return preview
+}
+
+// sendList builds a page of the scheduled messages of the user of an
+// interaction, or of everyone in the server
+func sendList(i *discordgo.InteractionCreate, page int, all bool) (*discordgo.InteractionResponseData, error) {
+	jobs, err := listJobs(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	userID := invokingUser(i).ID
+	var listed []*ScheduledJob
+	for _, job := range jobs {
+		if job.Kind == "send" && ((all && job.GuildID == i.GuildID) || (!all && job.UserID == userID)) {
+			listed = append(listed, job)
+		}
+	}
+	data := &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+		Components: []discordgo.MessageComponent{},
+	}
+	if len(listed) == 0 {
+		data.Content = tr(i, "send.listEmpty")
+		return data, nil
+	}
+	pages := (len(listed) + SEND_LIST_PAGE_SIZE - 1) / SEND_LIST_PAGE_SIZE
+	page = min(max(page, 1), pages)
+	lines := []string{tr(i, "send.listHeader", page, pages)}
+	for _, job := range listed[(page - 1) * SEND_LIST_PAGE_SIZE:min(page * SEND_LIST_PAGE_SIZE, len(listed))] {
+		line := tr(i, "send.listEntry", job.ID, job.Time / 1000, job.Time / 1000, job.ChannelID, job.UserID)
+		if job.Recurrence != nil {
+			line += " 🔁 `" + job.Recurrence.Cron + "`"
+		}
+		if job.Status != JOB_PENDING {
+			line += " (" + job.Status + ")"
+		}
+		lines = append(lines, line, "> " + jobPreview(job))
+	}
+	data.Content = strings.Join(lines, "\n")
+	if pages > 1 {
+		data.Components = []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label: "◀",
+						Style: discordgo.SecondaryButton,
+						CustomID: fmt.Sprintf("sendList:%d:%t", page - 1, all),
+						Disabled: page == 1,
+					},
+					discordgo.Button{
+						Label: "▶",
+						Style: discordgo.SecondaryButton,
+						CustomID: fmt.Sprintf("sendList:%d:%t", page + 1, all),
+						Disabled: page == pages,
+					},
+				},
+			},
+		}
+	}
+	return data, nil
+}
+
+func handleSendList(s *discordgo.Session, i *discordgo.InteractionCreate, options *sendListOptions) {
+	if options.All && !isGuildManager(i) {
+		respondOptionError(s, i, localizedError{"send.notManager", nil})
+		return
+	}
+	data, err := sendList(i, options.Page, options.All)
+	if err != nil {
+		log.Println("Error listing scheduled jobs", err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+}
+
+func handleSendEdit(s *discordgo.Session, i *discordgo.InteractionCreate, options *sendEditOptions) {
+	if options.Time == "" && options.Content == "" {
+		respondOptionError(s, i, localizedError{"send.nothingToEdit", nil})
+		return
+	}
+	ctx := context.Background()
+	job, err := getJob(ctx, options.ID)
+	if err != nil {
+		log.Println("Error getting scheduled job", err)
+		return
+	}
+	if job == nil || !canManageJob(i, job) {
+		respondOptionError(s, i, localizedError{"send.notFound", []any{options.ID}})
+		return
+	}
+	var sendTime int64
+	if options.Time != "" {
+		createdTime, err := discordgo.SnowflakeTimestamp(i.ID)
+		if err != nil {
+			log.Println("Error getting interaction time", err)
+			return
+		}
+		t, err := parseTime(options.Time, createdTime, userLocation(ctx, invokingUser(i).ID, i.GuildID))
+		if err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+		// Recurring jobs move to their first occurrence at or after the time
+		if job.Recurrence != nil {
+			var ok bool
+			t, ok = job.Recurrence.next(t.Add(-time.Millisecond))
+			if !ok {
+				respondOptionError(s, i, localizedError{"send.neverRepeats", []any{job.Recurrence.Cron}})
+				return
+			}
+		}
+		sendTime = t.UnixMilli()
+	}
+	updated, err := updateJob(ctx, options.ID, func(job *ScheduledJob) bool {
+		if sendTime != 0 {
+			job.Time = sendTime
+		}
+		if options.Content != "" {
+			if job.Message == nil {
+				job.Message = &ScheduledMessage{}
+			}
+			job.Message.Content = options.Content
+		}
+		return true
+	})
+	if err != nil {
+		log.Println("Error editing scheduled job", err)
+		return
+	}
+	if updated == nil {
+		respondOptionError(s, i, localizedError{"send.wrongStatus", []any{options.ID, job.Status}})
+		return
+	}
+	respondSend(s, i, tr(i, "send.edited", updated.ID, updated.Time / 1000, updated.Time / 1000))
+}
+
+func handleSendCancel(s *discordgo.Session, i *discordgo.InteractionCreate, id string) {
+	ctx := context.Background()
+	job, err := getJob(ctx, id)
+	if err != nil {
+		log.Println("Error getting scheduled job", err)
+		return
+	}
+	if job == nil || !canManageJob(i, job) {
+		respondOptionError(s, i, localizedError{"send.notFound", []any{id}})
+		return
+	}
+	cancelled, err := cancelJob(ctx, id)
+	if err != nil {
+		log.Println("Error cancelling scheduled job", err)
+	}
+	if cancelled == nil {
+		respondOptionError(s, i, localizedError{"send.wrongStatus", []any{id, job.Status}})
+		return
+	}
+	respondSend(s, i, tr(i, "send.cancelledJob", id, cancelled.Time / 1000))
+}
+
+// respondSend answers a /send command privately
+func respondSend(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func handleSendSchedule(s *discordgo.Session, i *discordgo.InteractionCreate, options *sendScheduleOptions) {
+	message := &ScheduledMessage{
+		Content: options.Content,
+		AllowedMentions: options.Mentions,
+	}
+	if options.Title != "" || options.Description != "" {
+		message.Embed = &ScheduledEmbed{
+			Title: options.Title,
+			Description: options.Description,
+		}
+		if options.Color != "" {
+			color, err := parseColor(options.Color)
+			if err != nil {
+				respondOptionError(s, i, err)
+				return
+			}
+			message.Embed.Color = color
+		}
+	}
+	if options.File != nil && firebase.Bucket == nil {
+		respondOptionError(s, i, localizedError{"send.noStorage", nil})
+		return
+	}
+	if options.Time == "" && options.Repeat == "" {
+		respondOptionError(s, i, localizedError{"option.missing", []any{"time"}})
+		return
+	}
+	if options.Repeat == "" && options.Until != "" {
+		respondOptionError(s, i, localizedError{"option.requires", []any{"until", "repeat"}})
+		return
+	}
+	if options.Repeat == "" && options.Count != 0 {
+		respondOptionError(s, i, localizedError{"option.requires", []any{"count", "repeat"}})
+		return
+	}
+	createdTime, err := discordgo.SnowflakeTimestamp(i.ID)
+	if err != nil {
+		log.Println("Error getting interaction time", err)
+		return
+	}
+	user := invokingUser(i)
+	channelID := i.ChannelID
+	if options.DM {
+		channel, err := s.UserChannelCreate(user.ID)
+		if err != nil {
+			log.Println("Error creating DM channel", err)
+			return
+		}
+		channelID = channel.ID
+	} else {
+		if options.Channel != nil {
+			channelID = options.Channel.ID
+		}
+		checkMessage := *message
+		if options.File != nil {
+			checkMessage.Attachments = []ScheduledAttachment{{}}
+		}
+		if err := checkSendTarget(s, channelID, user.ID, &checkMessage); err != nil {
+			if _, ok := err.(localizedError); !ok {
+				log.Println("Error checking scheduled message channel", err)
+			}
+			respondOptionError(s, i, err)
+			return
+		}
+	}
+	loc := userLocation(context.Background(), user.ID, i.GuildID)
+	sendTime := createdTime
+	if options.Time != "" {
+		sendTime, err = parseTime(options.Time, createdTime, loc)
+		if err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+	}
+	var recurrence *Recurrence
+	if options.Repeat != "" {
+		cron, err := parseRepeat(options.Repeat)
+		if err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+		recurrence = &Recurrence{
+			Cron: cron,
+			Timezone: loc.String(),
+			MaxOccurrences: options.Count,
+		}
+		if options.Until != "" {
+			until, err := parseTime(options.Until, createdTime, loc)
+			if err != nil {
+				respondOptionError(s, i, err)
+				return
+			}
+			recurrence.Until = until.UnixMilli()
+		}
+		// The first occurrence is the first one at or after the given time
+		var ok bool
+		sendTime, ok = recurrence.next(sendTime.Add(-time.Millisecond))
+		if !ok {
+			respondOptionError(s, i, localizedError{"send.neverRepeats", []any{options.Repeat}})
+			return
+		}
+	}
+	job := &ScheduledJob{
+		Kind: "send",
+		GuildID: i.GuildID,
+		ChannelID: channelID,
+		DM: options.DM,
+		UserID: user.ID,
+		Time: sendTime.UnixMilli(),
+		MissedPolicy: options.Missed,
+		CreatedAt: createdTime.UnixMilli(),
+		Locale: interactionLocale(i),
+		Message: message,
+		Recurrence: recurrence,
+	}
+
+	// Ask for the content in a modal, which allows multiple lines, if there
+	// would be nothing to send
+	if message.Content == "" && message.Embed == nil && options.File == nil {
+		addPendingSend(i.ID, &pendingSend{job: job, created: createdTime})
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: "sendModal:" + i.ID,
+				Title: tr(i, "send.modalTitle"),
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID: "content",
+								Label: tr(i, "send.modalContent"),
+								Style: discordgo.TextInputParagraph,
+								Required: true,
+								MaxLength: 2000,
+							},
+						},
+					},
+				},
+			},
+		})
+		return
+	}
+	confirmSend(s, i, i.ID, &pendingSend{job: job, file: options.File, created: createdTime})
+}
+
+func handleSendPause(s *discordgo.Session, i *discordgo.InteractionCreate, id string, paused bool) {
+	ctx := context.Background()
+	job, err := getJob(ctx, id)
+	if err != nil {
+		log.Println("Error getting scheduled job", err)
+		return
+	}
+	if job == nil || !canManageJob(i, job) {
+		respondOptionError(s, i, localizedError{"send.notFound", []any{id}})
+		return
+	}
+	updated, err := setJobPaused(ctx, id, paused)
+	if err != nil {
+		log.Println("Error pausing scheduled job", err)
+		return
+	}
+	if updated == nil {
+		respondOptionError(s, i, localizedError{"send.wrongStatus", []any{id, job.Status}})
+		return
+	}
+	if paused {
+		respondSend(s, i, tr(i, "send.paused", id))
+	} else {
+		respondSend(s, i, tr(i, "send.resumed", id, updated.Time / 1000, updated.Time / 1000))
+	}
+}
+
+func init() {
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
+		Name:        "send",
+		Description: "Schedule and manage messages",
+		Options: commandOptions(sendOptions{}),
+		IntegrationTypes: GUILD_INTEGRATIONS,
+		Contexts: GUILD_CONTEXTS,
+	}))
 	CommandHandlers["send"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		sendTime := i.ApplicationCommandData().Options[0].IntValue()
+		var options sendOptions
+		if err := parseOptions(i.ApplicationCommandData(), &options); err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+		switch {
+		case options.Schedule != nil:
+			handleSendSchedule(s, i, options.Schedule)
+		case options.List != nil:
+			handleSendList(s, i, options.List)
+		case options.Edit != nil:
+			handleSendEdit(s, i, options.Edit)
+		case options.Cancel != nil:
+			handleSendCancel(s, i, options.Cancel.ID)
+		case options.Pause != nil:
+			handleSendPause(s, i, options.Pause.ID, true)
+		case options.Resume != nil:
+			handleSendPause(s, i, options.Resume.ID, false)
+		}
+	}
+	AutocompleteHandlers["send"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		focused := focusedOption(i.ApplicationCommandData().Options)
+		if focused == nil || focused.Name != "id" {
+			return
+		}
+		ctx := context.Background()
+		jobs, err := listJobs(ctx)
+		if err != nil {
+			log.Println("Error listing scheduled jobs", err)
+			return
+		}
+		userID := invokingUser(i).ID
+		loc := userLocation(ctx, userID, i.GuildID)
+		query := strings.ToLower(focused.StringValue())
+		choices := []*discordgo.ApplicationCommandOptionChoice{}
+		for _, job := range jobs {
+			if len(choices) == 25 {
+				break
+			}
+			if job.Kind != "send" || job.UserID != userID || (job.Status != JOB_PENDING && job.Status != JOB_PAUSED) {
+				continue
+			}
+			preview := jobPreview(job)
+			if !strings.Contains(strings.ToLower(job.ID + " " + preview), query) {
+				continue
+			}
+			name := fmt.Sprintf("%s · %s · %s", job.ID, time.UnixMilli(job.Time).In(loc).Format("2006-01-02 15:04 MST"), preview)
+			if runes := []rune(name); len(runes) > 100 {
+				name = string(runes[:100])
+			}
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name: name,
+				Value: job.ID,
+			})
+		}
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
 			Data: &discordgo.InteractionResponseData{
-				Content: "Scheduled message send.",
-				Flags: discordgo.MessageFlagsEphemeral,
+				Choices: choices,
 			},
 		})
-		time.Sleep(time.Duration(sendTime * 1_000_000 - time.Now().UnixNano()))
-		sendScheduledMessage(i.ChannelID)
 	}
-}
\ No newline at end of file
+	ComponentHandlers["sendList"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		args := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(args) != 3 {
+			return
+		}
+		page, _ := strconv.Atoi(args[1])
+		data, err := sendList(i, page, args[2] == "true")
+		if err != nil {
+			log.Println("Error listing scheduled jobs", err)
+			return
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: data,
+		})
+	}
+	ModalHandlers["sendModal"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		_, id, _ := strings.Cut(i.ModalSubmitData().CustomID, ":")
+		pending := takePendingSend(id)
+		if pending == nil {
+			respondOptionError(s, i, localizedError{"send.expired", nil})
+			return
+		}
+		pending.job.Message.Content = modalValue(i.ModalSubmitData(), "content")
+		confirmSend(s, i, id, pending)
+	}
+	ComponentHandlers["sendConfirm"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		_, id, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+		pending := takePendingSend(id)
+		if pending == nil {
+			respondOptionError(s, i, localizedError{"send.expired", nil})
+			return
+		}
+		completeSend(s, i, pending.job, pending.file)
+	}
+	ComponentHandlers["sendCancel"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		_, id, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+		takePendingSend(id)
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content: tr(i, "send.cancelled"),
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+	}
+	jobRunners["send"] = prepareScheduledMessage
+}
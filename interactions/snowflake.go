@@ -0,0 +1,135 @@
+package interactions
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord snowflakes are milliseconds since DISCORD_EPOCH in the top 42 bits,
+// then a 5 bit worker ID, a 5 bit process ID and a 12 bit increment
+const DISCORD_EPOCH = 1420070400000
+const SNOWFLAKE_TIMESTAMP_SHIFT = 22
+
+type snowflakeOptions struct {
+	Decode *snowflakeDecodeOptions `option:"decode" description:"Show what a Discord ID is made of"`
+	Encode *snowflakeEncodeOptions `option:"encode" description:"Get the smallest and largest IDs for a time, for before and after searches"`
+	Diff *snowflakeDiffOptions `option:"diff" description:"Show how many milliseconds apart two Discord IDs are"`
+}
+
+type snowflakeDecodeOptions struct {
+	ID string `option:"id" description:"Discord ID" required:"true"`
+}
+
+type snowflakeEncodeOptions struct {
+	Time string `option:"time" description:"Time, like 2026-12-31 23:59:59.500, in 2h or Unix time in ms" required:"true"`
+}
+
+type snowflakeDiffOptions struct {
+	A string `option:"a" description:"First Discord ID" required:"true"`
+	B string `option:"b" description:"Second Discord ID" required:"true"`
+}
+
+type snowflake struct {
+	ID uint64
+	Time time.Time
+	Worker uint64
+	Process uint64
+	Increment uint64
+}
+
+func parseSnowflake(id string) (snowflake, error) {
+	value, err := strconv.ParseUint(strings.TrimSpace(id), 10, 64)
+	if err != nil {
+		return snowflake{}, localizedError{"snowflake.invalid", []any{id}}
+	}
+	return snowflake{
+		ID: value,
+		Time: time.UnixMilli(int64(value >> SNOWFLAKE_TIMESTAMP_SHIFT) + DISCORD_EPOCH),
+		Worker: value >> 17 & 0x1f,
+		Process: value >> 12 & 0x1f,
+		Increment: value & 0xfff,
+	}, nil
+}
+
+// snowflakeRange returns the smallest and largest snowflakes created in the
+// millisecond of t
+func snowflakeRange(t time.Time) (uint64, uint64) {
+	minID := uint64(t.UnixMilli() - DISCORD_EPOCH) << SNOWFLAKE_TIMESTAMP_SHIFT
+	return minID, minID | (1 << SNOWFLAKE_TIMESTAMP_SHIFT - 1)
+}
+
+func init() {
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
+		Name: "snowflake",
+		Description: "Decode, encode and compare Discord IDs",
+		Options: commandOptions(snowflakeOptions{}),
+		IntegrationTypes: ALL_INTEGRATIONS,
+		Contexts: ALL_CONTEXTS,
+	}))
+	CommandHandlers["snowflake"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		var options snowflakeOptions
+		if err := parseOptions(i.ApplicationCommandData(), &options); err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+		var content string
+		switch {
+		case options.Decode != nil:
+			id, err := parseSnowflake(options.Decode.ID)
+			if err != nil {
+				respondOptionError(s, i, err)
+				return
+			}
+			content = tr(i, "snowflake.decoded", id.ID, id.Time.Unix(), id.Time.UnixMilli(), formatTimestamp(id.Time, "iso"), id.Worker, id.Process, id.Increment)
+		case options.Encode != nil:
+			createdTime, err := discordgo.SnowflakeTimestamp(i.ID)
+			if err != nil {
+				log.Println("Error getting interaction time", err)
+				return
+			}
+			t, err := parseTime(options.Encode.Time, createdTime, userLocation(context.Background(), invokingUser(i).ID, i.GuildID))
+			if err != nil {
+				respondOptionError(s, i, err)
+				return
+			}
+			if t.UnixMilli() < DISCORD_EPOCH {
+				respondOptionError(s, i, localizedError{"snowflake.beforeEpoch", nil})
+				return
+			}
+			minID, maxID := snowflakeRange(t)
+			content = tr(i, "snowflake.encoded", t.Unix(), t.UnixMilli(), minID, maxID)
+		case options.Diff != nil:
+			a, err := parseSnowflake(options.Diff.A)
+			if err != nil {
+				respondOptionError(s, i, err)
+				return
+			}
+			b, err := parseSnowflake(options.Diff.B)
+			if err != nil {
+				respondOptionError(s, i, err)
+				return
+			}
+			delta := b.Time.Sub(a.Time).Milliseconds()
+			content = tr(i, "snowflake.diff", a.ID, a.Time.UnixMilli(), b.ID, b.Time.UnixMilli(), delta)
+			if delta == 0 {
+				// IDs from the same millisecond are only ordered if they came
+				// from the same worker and process
+				content += "\n" + tr(i, "snowflake.sameMillisecond")
+			} else if delta > 0 {
+				content += "\n" + tr(i, "snowflake.first", a.ID, formatAge(time.Duration(delta) * time.Millisecond))
+			} else {
+				content += "\n" + tr(i, "snowflake.first", b.ID, formatAge(time.Duration(-delta) * time.Millisecond))
+			}
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: content,
+			},
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package interactions
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Times can be given as:
+//
+//	1767225599500            Unix epoch time in milliseconds
+//	<t:1767225599:F>         Discord timestamp
+//	in 2h30m, in 1 day 3h    relative to now
+//	2026-12-31 23:59:59.500  date with an optional time
+//	tomorrow 9am, fri 17:00  day name with an optional time
+//	17:00, 9:30pm            next occurrence of a time
+//
+// Dates and times of day are in the given location, and days without a time
+// start at midnight.
+
+// Shorter numbers are too early to be epoch milliseconds anyone means, and
+// are more likely a mistyped duration
+const MIN_EPOCH_MS_DIGITS = 12
+
+var discordTimestampPattern = regexp.MustCompile(`^<t:(-?\d+)(?::[tTdDfFR])?>$`)
+var durationPattern = regexp.MustCompile(`(\d+)\s*([a-z]+)`)
+var timeOfDayPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2})(?::(\d{2})(?:\.(\d{1,3}))?)?)?\s*(am|pm)?$`)
+
+var DATE_LAYOUTS = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+var DURATION_UNITS = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"millisecond": time.Millisecond,
+	"milliseconds": time.Millisecond,
+	"s": time.Second,
+	"sec": time.Second,
+	"secs": time.Second,
+	"second": time.Second,
+	"seconds": time.Second,
+	"m": time.Minute,
+	"min": time.Minute,
+	"mins": time.Minute,
+	"minute": time.Minute,
+	"minutes": time.Minute,
+	"h": time.Hour,
+	"hr": time.Hour,
+	"hrs": time.Hour,
+	"hour": time.Hour,
+	"hours": time.Hour,
+	"d": 24 * time.Hour,
+	"day": 24 * time.Hour,
+	"days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"week": 7 * 24 * time.Hour,
+	"weeks": 7 * 24 * time.Hour,
+}
+
+var WEEKDAY_NAMES = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"sunday": time.Sunday,
+	"mon": time.Monday,
+	"monday": time.Monday,
+	"tue": time.Tuesday,
+	"tues": time.Tuesday,
+	"tuesday": time.Tuesday,
+	"wed": time.Wednesday,
+	"wednesday": time.Wednesday,
+	"thu": time.Thursday,
+	"thurs": time.Thursday,
+	"thursday": time.Thursday,
+	"fri": time.Friday,
+	"friday": time.Friday,
+	"sat": time.Saturday,
+	"saturday": time.Saturday,
+}
+
+// parseTime resolves a time given by a user
+func parseTime(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	raw := strings.TrimSpace(input)
+	value := strings.ToLower(raw)
+	invalid := localizedError{"time.invalid", []any{input}}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if len(strings.TrimLeft(value, "+-")) < MIN_EPOCH_MS_DIGITS {
+			return time.Time{}, localizedError{"time.shortEpoch", []any{raw}}
+		}
+		return time.UnixMilli(ms), nil
+	}
+	if match := discordTimestampPattern.FindStringSubmatch(raw); match != nil {
+		seconds, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return time.Time{}, invalid
+		}
+		return time.Unix(seconds, 0), nil
+	}
+	if rest, ok := strings.CutPrefix(value, "in "); ok {
+		duration, ok := parseDuration(rest)
+		if !ok {
+			return time.Time{}, invalid
+		}
+		return now.Add(duration), nil
+	}
+	for _, layout := range DATE_LAYOUTS {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+
+	now = now.In(loc)
+	day, clock, _ := strings.Cut(value, " ")
+	daysAhead := -1
+	switch day {
+	case "today":
+		daysAhead = 0
+	case "tomorrow":
+		daysAhead = 1
+	default:
+		if weekday, ok := WEEKDAY_NAMES[day]; ok {
+			daysAhead = (int(weekday) - int(now.Weekday()) + 7) % 7
+		} else {
+			// A time of day on its own
+			clock = value
+		}
+	}
+	hour, minute, second, ms := 0, 0, 0, 0
+	if clock != "" {
+		var ok bool
+		hour, minute, second, ms, ok = parseTimeOfDay(strings.TrimSpace(clock), daysAhead >= 0)
+		if !ok {
+			return time.Time{}, invalid
+		}
+	}
+	t := time.Date(now.Year(), now.Month(), now.Day() + max(daysAhead, 0), hour, minute, second, ms * int(time.Millisecond), loc)
+	if daysAhead < 0 && !t.After(now) {
+		t = time.Date(now.Year(), now.Month(), now.Day() + 1, hour, minute, second, ms * int(time.Millisecond), loc)
+	} else if day != "today" && daysAhead == 0 && !t.After(now) {
+		// The weekday is today but that time has passed, so it means next week
+		t = time.Date(now.Year(), now.Month(), now.Day() + 7, hour, minute, second, ms * int(time.Millisecond), loc)
+	}
+	return t, nil
+}
+
+// parseDuration parses durations like "2h30m" or "1 day 3 hours"
+func parseDuration(value string) (time.Duration, bool) {
+	matches := durationPattern.FindAllStringSubmatchIndex(value, -1)
+	if matches == nil {
+		return 0, false
+	}
+	var duration time.Duration
+	end := 0
+	for _, match := range matches {
+		if strings.TrimSpace(value[end:match[0]]) != "" {
+			return 0, false
+		}
+		end = match[1]
+		n, err := strconv.ParseInt(value[match[2]:match[3]], 10, 64)
+		unit, ok := DURATION_UNITS[value[match[4]:match[5]]]
+		if err != nil || !ok {
+			return 0, false
+		}
+		duration += time.Duration(n) * unit
+	}
+	if strings.TrimSpace(value[end:]) != "" {
+		return 0, false
+	}
+	return duration, true
+}
+
+// parseTimeOfDay parses times like "17:00", "9am" or "23:59:59.500". A bare
+// hour is only accepted after a day name, where it can't be mistaken for
+// anything else.
+func parseTimeOfDay(value string, allowBareHour bool) (hour, minute, second, ms int, ok bool) {
+	match := timeOfDayPattern.FindStringSubmatch(value)
+	if match == nil || (match[2] == "" && match[5] == "" && !allowBareHour) {
+		return 0, 0, 0, 0, false
+	}
+	hour, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	if match[3] != "" {
+		second, _ = strconv.Atoi(match[3])
+	}
+	if match[4] != "" {
+		// ".5" is half a second
+		ms, _ = strconv.Atoi((match[4] + "00")[:3])
+	}
+	switch match[5] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, 0, false
+		}
+		hour %= 12
+		if match[5] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0, 0, 0, 0, false
+	}
+	return hour, minute, second, ms, true
+}
@@ -0,0 +1,66 @@
+package interactions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Wednesday
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, loc)
+	tests := []struct {
+		input string
+		want time.Time
+		// Key of the localized error, empty if the input is valid
+		errKey string
+	}{
+		{"1767225599500", time.UnixMilli(1767225599500), ""},
+		{" 1767225599500 ", time.UnixMilli(1767225599500), ""},
+		{"12345", time.Time{}, "time.shortEpoch"},
+		{"<t:1767225599:F>", time.Unix(1767225599, 0), ""},
+		{"<t:1767225599>", time.Unix(1767225599, 0), ""},
+		{"in 2h30m", now.Add(150 * time.Minute), ""},
+		{"in 1 day 3 hours", now.Add(27 * time.Hour), ""},
+		{"In 90s", now.Add(90 * time.Second), ""},
+		{"in soon", time.Time{}, "time.invalid"},
+		{"in 2h later", time.Time{}, "time.invalid"},
+		{"2026-12-31 23:59:59.500", time.Date(2026, 12, 31, 23, 59, 59, 500 * int(time.Millisecond), loc), ""},
+		{"2026-12-31T08:15", time.Date(2026, 12, 31, 8, 15, 0, 0, loc), ""},
+		{"2026-12-31", time.Date(2026, 12, 31, 0, 0, 0, 0, loc), ""},
+		{"2026-12-31T23:59:59Z", time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), ""},
+		{"today 9am", time.Date(2026, 3, 11, 9, 0, 0, 0, loc), ""},
+		{"tomorrow 9am", time.Date(2026, 3, 12, 9, 0, 0, 0, loc), ""},
+		{"tomorrow", time.Date(2026, 3, 12, 0, 0, 0, 0, loc), ""},
+		{"fri 17:00", time.Date(2026, 3, 13, 17, 0, 0, 0, loc), ""},
+		{"friday 5", time.Date(2026, 3, 13, 5, 0, 0, 0, loc), ""},
+		// Today's weekday at a time that has passed is next week
+		{"wed 9am", time.Date(2026, 3, 18, 9, 0, 0, 0, loc), ""},
+		{"wed 11am", time.Date(2026, 3, 11, 11, 0, 0, 0, loc), ""},
+		{"17:00", time.Date(2026, 3, 11, 17, 0, 0, 0, loc), ""},
+		{"9:30pm", time.Date(2026, 3, 11, 21, 30, 0, 0, loc), ""},
+		{"9:30", time.Date(2026, 3, 12, 9, 30, 0, 0, loc), ""},
+		{"23:59:59.5", time.Date(2026, 3, 11, 23, 59, 59, 500 * int(time.Millisecond), loc), ""},
+		{"9", time.Time{}, "time.shortEpoch"},
+		{"13pm", time.Time{}, "time.invalid"},
+		{"24:00", time.Time{}, "time.invalid"},
+		{"someday", time.Time{}, "time.invalid"},
+	}
+	for _, test := range tests {
+		got, err := parseTime(test.input, now, loc)
+		if test.errKey != "" {
+			if e, ok := err.(localizedError); !ok || e.Key != test.errKey {
+				t.Errorf("parseTime(%q) error = %v, want %s", test.input, err, test.errKey)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseTime(%q) error = %v", test.input, err)
+		} else if !got.Equal(test.want) {
+			t.Errorf("parseTime(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
@@ -1,27 +1,127 @@
 package interactions
 
 import (
-	"strconv"
+	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 	"github.com/bwmarrin/discordgo"
 )
 
+// Discord timestamp styles in the order they are shown
+var TIMESTAMP_STYLES = []string{"t", "T", "d", "D", "f", "F", "R"}
+
+const ISO_8601_MILLI = "2006-01-02T15:04:05.000Z07:00"
+
+// formatTimestamp formats a time for posting. Formats are the Discord
+// timestamp styles, "ms" for Unix epoch milliseconds and "iso" for ISO 8601
+// in UTC.
+func formatTimestamp(t time.Time, format string) string {
+	switch format {
+	case "ms":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case "iso":
+		return t.UTC().Format(ISO_8601_MILLI)
+	}
+	return fmt.Sprintf("<t:%d:%s>", t.Unix(), format)
+}
+
+// formatAge formats a duration like 1d 2h 3m 4.567s
+func formatAge(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	var parts []string
+	if days := d / (24 * time.Hour); days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours := d / time.Hour % 24; hours > 0 || len(parts) > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes := d / time.Minute % 60; minutes > 0 || len(parts) > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	parts = append(parts, fmt.Sprintf("%.3fs", (d % time.Minute).Seconds()))
+	return sign + strings.Join(parts, " ")
+}
+
 func init() {
-	Commands = append(Commands, &discordgo.ApplicationCommand{
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
 		Name: "Timestamp",
 		Type: discordgo.MessageApplicationCommand,
-	})
+		IntegrationTypes: ALL_INTEGRATIONS,
+		Contexts: ALL_CONTEXTS,
+	}))
 	CommandHandlers["Timestamp"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		mTime, err := discordgo.SnowflakeTimestamp(i.ApplicationCommandData().TargetID)
 		if err != nil {
 			log.Println("Error getting message time", err)
 			return
 		}
+		createdTime, err := discordgo.SnowflakeTimestamp(i.ID)
+		if err != nil {
+			log.Println("Error getting interaction time", err)
+			return
+		}
+		loc := userLocation(context.Background(), invokingUser(i).ID, i.GuildID)
+		local := mTime.In(loc)
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		sinceMidnight := local.Sub(midnight)
+
+		lines := []string{tr(i, "timestamp.header", mTime.Unix())}
+		for _, style := range TIMESTAMP_STYLES {
+			timestamp := formatTimestamp(mTime, style)
+			lines = append(lines, fmt.Sprintf("`%s` %s", timestamp, timestamp))
+		}
+		lines = append(lines,
+			tr(i, "timestamp.unix", mTime.UnixMilli()),
+			tr(i, "timestamp.isoUTC", formatTimestamp(mTime, "iso")),
+			tr(i, "timestamp.isoLocal", loc.String(), local.Format(ISO_8601_MILLI)),
+			tr(i, "timestamp.age", formatAge(createdTime.Sub(mTime)), mTime.Unix()),
+			tr(i, "timestamp.midnight", loc.String(), sinceMidnight.Milliseconds(), local.Format("15:04:05.000")),
+		)
+
+		var buttons []discordgo.MessageComponent
+		for _, format := range append(TIMESTAMP_STYLES, "ms", "iso") {
+			buttons = append(buttons, discordgo.Button{
+				Label: tr(i, "timestamp.format." + format),
+				Style: discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("timestampPost:%d:%s", mTime.UnixMilli(), format),
+			})
+		}
+		var rows []discordgo.MessageComponent
+		for len(buttons) > 0 {
+			n := min(len(buttons), 5)
+			rows = append(rows, discordgo.ActionsRow{Components: buttons[:n]})
+			buttons = buttons[n:]
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: strings.Join(lines, "\n"),
+				Flags: discordgo.MessageFlagsEphemeral,
+				Components: rows,
+			},
+		})
+	}
+	ComponentHandlers["timestampPost"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		args := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(args) != 3 {
+			return
+		}
+		ms, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return
+		}
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: strconv.FormatInt(mTime.UnixMilli(), 10),
+				Content: formatTimestamp(time.UnixMilli(ms), args[2]),
 			},
 		})
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,198 @@
+package interactions
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Directories IANA timezones are read from for autocomplete, in the order Go
+// looks for them
+var ZONEINFO_DIRS = []string{"/usr/share/zoneinfo", "/usr/share/lib/zoneinfo", "/usr/lib/locale/TZ"}
+
+type timezoneOptions struct {
+	Set *timezoneSetOptions `option:"set" description:"Set your timezone, used for times you give and times the bot shows you"`
+	Clear *struct{} `option:"clear" description:"Go back to the server's default timezone"`
+	Server *timezoneSetOptions `option:"server" description:"Set the server's default timezone"`
+	Show *struct{} `option:"show" description:"Show your timezone and the server's default"`
+}
+
+type timezoneSetOptions struct {
+	Zone string `option:"zone" description:"IANA timezone, like America/New_York or Asia/Kolkata" required:"true" autocomplete:"true"`
+}
+
+var timezoneNames []string
+
+// loadTimezoneNames lists the timezones in the first zoneinfo directory that
+// exists, leaving out legacy and leap second variants
+func loadTimezoneNames() []string {
+	var names []string
+	for _, dir := range ZONEINFO_DIRS {
+		filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			name, _ := filepath.Rel(dir, path)
+			if entry.IsDir() {
+				if name == "posix" || name == "right" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if name[0] < 'A' || name[0] > 'Z' || strings.Contains(name, ".") {
+				return nil
+			}
+			if _, err := time.LoadLocation(name); err == nil {
+				names = append(names, name)
+			}
+			return nil
+		})
+		if len(names) > 0 {
+			break
+		}
+	}
+	if len(names) == 0 {
+		names = []string{"UTC"}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseTimezone returns the location of an IANA timezone name
+func parseTimezone(zone string) (*time.Location, error) {
+	zone = strings.TrimSpace(zone)
+	// Local is the bot's own timezone, which users shouldn't depend on
+	if zone == "" || zone == "Local" {
+		return nil, localizedError{"timezone.invalid", []any{zone}}
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, localizedError{"timezone.invalid", []any{zone}}
+	}
+	return loc, nil
+}
+
+// timezoneChoices returns autocomplete choices for a timezone option. Names
+// are matched ignoring case, with spaces standing for underscores, and ones
+// with a part starting with the query come first.
+func timezoneChoices(value string) []*discordgo.ApplicationCommandOptionChoice {
+	query := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), " ", "_"))
+	var prefixMatches, otherMatches []string
+	for _, name := range timezoneNames {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, query) || strings.Contains(lower, "/" + query) {
+			prefixMatches = append(prefixMatches, name)
+		} else if strings.Contains(lower, query) {
+			otherMatches = append(otherMatches, name)
+		}
+	}
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, name := range append(prefixMatches, otherMatches...) {
+		if len(choices) == 25 {
+			break
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name: name,
+			Value: name,
+		})
+	}
+	return choices
+}
+
+func respondTimezone(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func init() {
+	timezoneNames = loadTimezoneNames()
+
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
+		Name: "timezone",
+		Description: "Set the timezone times are read and shown in",
+		Options: commandOptions(timezoneOptions{}),
+		IntegrationTypes: ALL_INTEGRATIONS,
+		Contexts: ALL_CONTEXTS,
+	}))
+	CommandHandlers["timezone"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		var options timezoneOptions
+		if err := parseOptions(i.ApplicationCommandData(), &options); err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
+		ctx := context.Background()
+		user := invokingUser(i)
+		switch {
+		case options.Set != nil:
+			loc, err := parseTimezone(options.Set.Zone)
+			if err != nil {
+				respondOptionError(s, i, err)
+				return
+			}
+			if err := setUserTimezone(ctx, user.ID, loc.String()); err != nil {
+				log.Println("Error saving user timezone", err)
+				respondOptionError(s, i, localizedError{"timezone.saveFailed", nil})
+				return
+			}
+			respondTimezone(s, i, tr(i, "timezone.set", loc.String(), formatLocalTime(time.Now(), loc)))
+		case options.Clear != nil:
+			if err := setUserTimezone(ctx, user.ID, ""); err != nil {
+				log.Println("Error saving user timezone", err)
+				respondOptionError(s, i, localizedError{"timezone.saveFailed", nil})
+				return
+			}
+			respondTimezone(s, i, tr(i, "timezone.cleared", getGuildConfig(i.GuildID).Timezone))
+		case options.Server != nil:
+			if i.GuildID == "" || !isGuildManager(i) {
+				respondOptionError(s, i, localizedError{"timezone.notManager", nil})
+				return
+			}
+			loc, err := parseTimezone(options.Server.Zone)
+			if err != nil {
+				respondOptionError(s, i, err)
+				return
+			}
+			if err := setGuildTimezone(ctx, i.GuildID, loc.String()); err != nil {
+				log.Println("Error saving guild timezone", err)
+				respondOptionError(s, i, localizedError{"timezone.saveFailed", nil})
+				return
+			}
+			respondTimezone(s, i, tr(i, "timezone.serverSet", loc.String()))
+		case options.Show != nil:
+			settings, err := getUserSettings(ctx, user.ID)
+			if err != nil {
+				log.Println("Error getting user settings", err)
+			}
+			content := tr(i, "timezone.showUnset")
+			if settings.Timezone != "" {
+				content = tr(i, "timezone.show", settings.Timezone)
+			}
+			loc := userLocation(ctx, user.ID, i.GuildID)
+			content += "\n" + tr(i, "timezone.showServer", getGuildConfig(i.GuildID).Timezone)
+			content += "\n" + tr(i, "timezone.now", loc.String(), formatLocalTime(time.Now(), loc))
+			respondTimezone(s, i, content)
+		}
+	}
+	AutocompleteHandlers["timezone"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		focused := focusedOption(i.ApplicationCommandData().Options)
+		if focused == nil || focused.Name != "zone" {
+			return
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{
+				Choices: timezoneChoices(focused.StringValue()),
+			},
+		})
+	}
+}
@@ -26,6 +26,10 @@ type Result struct {
 	ThumbsDown int64 `json:"thumbs_down"`
 }
 
+type udOptions struct {
+	Term string `option:"term" description:"Term to search for" required:"true"`
+}
+
 type UDResponse struct {
 	List []Result `json:"list"`
 }
@@ -68,23 +72,23 @@ func getNonEmptyStringWithMaxLen(s string, maxLen int) string {
 }
 
 func init() {
-	Commands = append(Commands, &discordgo.ApplicationCommand{
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
 		Name:        "ud",
 		Description: "Search Urban Dictionary",
-		Options: []*discordgo.ApplicationCommandOption{
-			{
-				Type: discordgo.ApplicationCommandOptionString,
-				Name: "term",
-				Description: "Term to search for",
-				Required: true,
-			},
-		},
-	})
+		Options: commandOptions(udOptions{}),
+		IntegrationTypes: ALL_INTEGRATIONS,
+		Contexts: ALL_CONTEXTS,
+	}))
 	CommandHandlers["ud"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		var options udOptions
+		if err := parseOptions(i.ApplicationCommandData(), &options); err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 		})
-		term := i.ApplicationCommandData().Options[0].StringValue()
+		term := options.Term
 		response, err := getUDResponse(term)
 		if err != nil {
 			log.Println("Getting Urban Dictionary response failed", err)
@@ -96,7 +100,7 @@ func init() {
 					{
 						Title: getNonEmptyStringWithMaxLen(term, 256),
 						Color: 0xff0000,
-						Description: "No results were found.",
+						Description: tr(i, "ud.noResults"),
 					},
 				},
 			})
@@ -113,20 +117,20 @@ func init() {
 						Color: 0xff8000,
 						Fields: []*discordgo.MessageEmbedField{
 							{
-								Name: "Definition",
+								Name: tr(i, "ud.definition"),
 								Value: getNonEmptyStringWithMaxLen(response.List[0].Definition, 1024),
 							},
 							{
-								Name: "Example",
+								Name: tr(i, "ud.example"),
 								Value: getNonEmptyStringWithMaxLen(response.List[0].Example, 1024),
 							},
 							{
-								Name: "Author",
+								Name: tr(i, "ud.author"),
 								Value: getNonEmptyStringWithMaxLen(response.List[0].Author, 1024),
 								Inline: true,
 							},
 							{
-								Name: "Date",
+								Name: tr(i, "ud.date"),
 								Value: fmt.Sprintf("<t:%d:d>", t.Unix()),
 								Inline: true,
 							},
@@ -0,0 +1,83 @@
+package interactions
+
+import (
+	"context"
+	"log"
+	"strings"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
+		Name: "User info",
+		Type: discordgo.UserApplicationCommand,
+		IntegrationTypes: ALL_INTEGRATIONS,
+		Contexts: ALL_CONTEXTS,
+	}))
+	CommandHandlers["User info"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		data := i.ApplicationCommandData()
+		user := data.Resolved.Users[data.TargetID]
+		if user == nil {
+			return
+		}
+		createdTime, err := discordgo.SnowflakeTimestamp(user.ID)
+		if err != nil {
+			log.Println("Error getting account creation time", err)
+			return
+		}
+		lines := []string{
+			tr(i, "userInfo.header", user.ID, user.ID),
+			tr(i, "userInfo.created", createdTime.Unix(), createdTime.Unix()),
+		}
+		// Members are only resolved when the command is used in a guild the
+		// user is in
+		if member := data.Resolved.Members[data.TargetID]; member != nil {
+			if !member.JoinedAt.IsZero() {
+				lines = append(lines, tr(i, "userInfo.joined", member.JoinedAt.Unix(), member.JoinedAt.Unix()))
+			}
+			if len(member.Roles) > 0 {
+				roles := make([]string, len(member.Roles))
+				for j, roleID := range member.Roles {
+					roles[j] = "<@&" + roleID + ">"
+				}
+				lines = append(lines, tr(i, "userInfo.roles", len(roles), strings.Join(roles, " ")))
+			} else {
+				lines = append(lines, tr(i, "userInfo.noRoles"))
+			}
+		}
+		count, fastest, err := userFirstMessageStats(context.Background(), i.GuildID, user.ID)
+		if err != nil {
+			log.Println("Error getting first message stats", err)
+		} else if count > 0 {
+			lines = append(lines, tr(i, "userInfo.firstMessages", count, fastest))
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: strings.Join(lines, "\n"),
+				Flags: discordgo.MessageFlagsEphemeral,
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.Button{
+								Label: tr(i, "userInfo.share"),
+								Style: discordgo.SecondaryButton,
+								CustomID: "userInfoShare:" + user.ID,
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	ComponentHandlers["userInfoShare"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		// Shared info mentions the user and their roles without pinging them
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: i.Message.Content,
+				AllowedMentions: &discordgo.MessageAllowedMentions{},
+			},
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package interactions
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+	"github.com/anishmit/gobot/firebase"
+)
+
+// UserSettings are stored under userSettings/<user>
+type UserSettings struct {
+	Timezone string `json:"timezone,omitempty"`
+}
+
+var userSettings = map[string]UserSettings{}
+var userSettingsMutex sync.RWMutex
+var userSettingsRef = firebase.DB.NewRef("userSettings")
+
+// getUserSettings returns the settings of a user, loading them on first use
+func getUserSettings(ctx context.Context, userID string) (UserSettings, error) {
+	userSettingsMutex.RLock()
+	settings, ok := userSettings[userID]
+	userSettingsMutex.RUnlock()
+	if ok {
+		return settings, nil
+	}
+	if err := userSettingsRef.Child(userID).Get(ctx, &settings); err != nil {
+		return settings, err
+	}
+	userSettingsMutex.Lock()
+	userSettings[userID] = settings
+	userSettingsMutex.Unlock()
+	return settings, nil
+}
+
+// setUserTimezone saves the timezone of a user, or removes it if empty
+func setUserTimezone(ctx context.Context, userID, timezone string) error {
+	settings, err := getUserSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+	settings.Timezone = timezone
+	if err := userSettingsRef.Child(userID).Set(ctx, settings); err != nil {
+		return err
+	}
+	userSettingsMutex.Lock()
+	userSettings[userID] = settings
+	userSettingsMutex.Unlock()
+	return nil
+}
+
+// userLocation returns the timezone times given by a user are in: their own
+// if they saved one, otherwise the guild's
+func userLocation(ctx context.Context, userID, guildID string) *time.Location {
+	timezone := getGuildConfig(guildID).Timezone
+	settings, err := getUserSettings(ctx, userID)
+	if err != nil {
+		log.Println("Error getting user settings", err)
+	} else if settings.Timezone != "" {
+		timezone = settings.Timezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Println("Error loading timezone", timezone, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// formatLocalTime formats a time on the wall clock of a timezone. Discord
+// timestamps render in the viewer's device timezone, so this is shown next to
+// them when the zone a time was read in matters.
+func formatLocalTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("2006-01-02 15:04:05 MST")
+}
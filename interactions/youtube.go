@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"github.com/bwmarrin/discordgo"
 	"strings"
+	"sync"
 	"github.com/jonas747/ogg"
 )
 
@@ -21,6 +22,53 @@ type SearchResult struct {
 	Duration string
 }	
 
+type ytOptions struct {
+	Query string `option:"query" description:"Search query" required:"true"`
+}
+
+type voicePlayer struct {
+	stopped chan struct{}
+	stopOnce sync.Once
+}
+
+func (p *voicePlayer) stop() {
+	p.stopOnce.Do(func() { close(p.stopped) })
+}
+
+var voicePlayers = map[string]*voicePlayer{}
+var voicePlayersMutex sync.Mutex
+
+// startVoicePlayer registers a player for a guild, stopping any player that
+// is already running there
+func startVoicePlayer(guildID string) *voicePlayer {
+	voicePlayersMutex.Lock()
+	defer voicePlayersMutex.Unlock()
+	if player, ok := voicePlayers[guildID]; ok {
+		player.stop()
+	}
+	player := &voicePlayer{stopped: make(chan struct{})}
+	voicePlayers[guildID] = player
+	return player
+}
+
+func stopVoicePlayer(guildID string) {
+	voicePlayersMutex.Lock()
+	defer voicePlayersMutex.Unlock()
+	if player, ok := voicePlayers[guildID]; ok {
+		player.stop()
+		delete(voicePlayers, guildID)
+	}
+}
+
+// removeVoicePlayer unregisters a player that finished on its own
+func removeVoicePlayer(guildID string, player *voicePlayer) {
+	voicePlayersMutex.Lock()
+	defer voicePlayersMutex.Unlock()
+	if voicePlayers[guildID] == player {
+		delete(voicePlayers, guildID)
+	}
+}
+
 func inVoiceChannel(s *discordgo.Session, guildID, userID string) (bool, string) {
 	// An error means that the user isn't in a VC
 	if voiceState, err := s.State.VoiceState(guildID, userID); err != nil {
@@ -87,46 +135,46 @@ func search(query string) ([]SearchResult, error) {
 
 
 func init() {
-	Commands = append(Commands, &discordgo.ApplicationCommand{
+	Commands = append(Commands, localizeCommand(&discordgo.ApplicationCommand{
 		Name:        "yt",
 		Description: "Play YouTube video",
-		Options: []*discordgo.ApplicationCommandOption{
-			{
-				Type: discordgo.ApplicationCommandOptionString,
-				Name: "query",
-				Description: "Search query",
-				Required: true,
-			},
-		},
-	})
+		Options: commandOptions(ytOptions{}),
+		IntegrationTypes: GUILD_INTEGRATIONS,
+		Contexts: GUILD_CONTEXTS,
+	}))
 	CommandHandlers["yt"] = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		var options ytOptions
+		if err := parseOptions(i.ApplicationCommandData(), &options); err != nil {
+			respondOptionError(s, i, err)
+			return
+		}
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 		})
 		// Check to make sure user is connected to a voice channel
-		if i.Member == nil {
+		if i.GuildID == "" {
 			s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
-				Content: "You must be in a guild voice channel to use this command.",
+				Content: tr(i, "yt.notInGuild"),
 			})
 			return
 		}
-		inVC, _ := inVoiceChannel(s, i.GuildID, i.Member.User.ID)
+		inVC, _ := inVoiceChannel(s, i.GuildID, invokingUser(i).ID)
 		if !inVC {
 			s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
-				Content: "You must be in a voice channel to use this command.",
+				Content: tr(i, "yt.notInVoice"),
 			})
 			return
 		}
-		searchQuery := i.ApplicationCommandData().Options[0].StringValue()
+		searchQuery := options.Query
 		searchResults, err := search(searchQuery)
 		if err != nil {
 			s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
-				Content: "Search request failed.",
+				Content: tr(i, "yt.searchFailed"),
 			})
 			return
 		} else if len(searchResults) == 0 {
 			s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
-				Content: fmt.Sprintf("No results found for %s.", searchQuery[:min(1978, len(searchQuery))]),
+				Content: tr(i, "yt.noResults", searchQuery[:min(1978, len(searchQuery))]),
 			})
 			return
 		}
@@ -138,7 +186,7 @@ func init() {
 				Description: searchResult.Duration,
 			})
 		}
-		placeholderText := fmt.Sprintf("Results for %s", searchQuery)
+		placeholderText := tr(i, "yt.results", searchQuery)
 		s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
 			Components: []discordgo.MessageComponent{
 				discordgo.ActionsRow{
@@ -161,15 +209,15 @@ func init() {
 		})
 		log.Println(i.Message.ReferencedMessage)
 		videoID := i.MessageComponentData().Values[0]
-		inVC, channelID := inVoiceChannel(s, i.GuildID, i.Member.User.ID)
+		inVC, channelID := inVoiceChannel(s, i.GuildID, invokingUser(i).ID)
 		if !inVC {
-			content := "You must be in a voice channel to use this command."
+			content := tr(i, "yt.notInVoice")
 			s.FollowupMessageEdit(i.Interaction, i.Message.ID, &discordgo.WebhookEdit{
 				Content: &content,
 			})
 			return
 		}
-		content := "Playing"
+		content := tr(i, "yt.playing")
 		s.FollowupMessageEdit(i.Interaction, i.Message.ID, &discordgo.WebhookEdit{
 			Content: &content,
 		})
@@ -197,15 +245,28 @@ func init() {
 		if err = cmd2.Start(); err != nil {
 			log.Println("Could not start command 2", err)
 		}
+		player := startVoicePlayer(i.GuildID)
+		defer removeVoicePlayer(i.GuildID, player)
 		decoder := ogg.NewPacketDecoder(ogg.NewDecoder(pipe))
 		voice.Speaking(true)
+		packets:
 		for {
 			packet, _, err := decoder.Decode()
 			if err != nil {
 				log.Println("Could not decode", err)
 				break
 			}
-			voice.OpusSend <- packet
+			select {
+			case voice.OpusSend <- packet:
+			case <-player.stopped:
+				log.Println("Voice player stopped")
+				for _, cmd := range []*exec.Cmd{cmd1, cmd2} {
+					if cmd.Process != nil {
+						cmd.Process.Kill()
+					}
+				}
+				break packets
+			}
 		}
 		log.Println("Finished sending packets")
 		voice.Speaking(false)
@@ -213,4 +274,16 @@ func init() {
 		cmd1.Wait()
 
 	}
+
+	// Stop playing when the bot leaves the guild or is disconnected from voice
+	GuildDeleteHandlers = append(GuildDeleteHandlers, func(s *discordgo.Session, g *discordgo.GuildDelete) {
+		if !g.Unavailable {
+			stopVoicePlayer(g.ID)
+		}
+	})
+	VoiceStateUpdateHandlers = append(VoiceStateUpdateHandlers, func(s *discordgo.Session, v *discordgo.VoiceStateUpdate) {
+		if v.UserID == s.State.User.ID && v.ChannelID == "" {
+			stopVoicePlayer(v.GuildID)
+		}
+	})
 }
\ No newline at end of file
@@ -1,32 +1,101 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 	"github.com/bwmarrin/discordgo"
 	"log"
 	"github.com/anishmit/gobot/interactions"
 	_ "github.com/joho/godotenv/autoload"
 )
 
-var s *discordgo.Session
+// Discord only allows one identify every 5 seconds per bucket
+const IDENTIFY_DELAY = 5 * time.Second
+
+var sessions []*discordgo.Session
+
+// getShardCount reads SHARD_COUNT, which is a number or "auto" to use the
+// count recommended by Discord
+func getShardCount(token string) int {
+	shardCount := os.Getenv("SHARD_COUNT")
+	switch shardCount {
+	case "":
+		return 1
+	case "auto":
+		s, err := discordgo.New(token)
+		if err != nil {
+			log.Fatalf("Invalid bot parameters: %v", err)
+		}
+		gatewayBot, err := s.GatewayBot()
+		if err != nil {
+			log.Fatalf("Cannot get recommended shard count: %v", err)
+		}
+		return gatewayBot.Shards
+	default:
+		count, err := strconv.Atoi(shardCount)
+		if err != nil || count < 1 {
+			log.Fatalf("Invalid SHARD_COUNT: %v", shardCount)
+		}
+		return count
+	}
+}
+
+// getShardIDs reads SHARD_ID, a comma separated list of the shards this
+// process runs, defaulting to all of them
+func getShardIDs(shardCount int) []int {
+	var shardIDs []int
+	if os.Getenv("SHARD_ID") == "" {
+		for shardID := 0; shardID < shardCount; shardID++ {
+			shardIDs = append(shardIDs, shardID)
+		}
+		return shardIDs
+	}
+	for _, value := range strings.Split(os.Getenv("SHARD_ID"), ",") {
+		shardID, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || shardID < 0 || shardID >= shardCount {
+			log.Fatalf("Invalid SHARD_ID: %v", value)
+		}
+		shardIDs = append(shardIDs, shardID)
+	}
+	return shardIDs
+}
 
 func init() {
-	var err error
-	s, err = discordgo.New("Bot " + os.Getenv("BOT_TOKEN"))
-	if err != nil {
-		log.Fatalf("Invalid bot parameters: %v", err)
+	token := "Bot " + os.Getenv("BOT_TOKEN")
+	shardCount := getShardCount(token)
+	for _, shardID := range getShardIDs(shardCount) {
+		s, err := discordgo.New(token)
+		if err != nil {
+			log.Fatalf("Invalid bot parameters: %v", err)
+		}
+		s.ShardID = shardID
+		s.ShardCount = shardCount
+		sessions = append(sessions, s)
 	}
 }
 
-func main() {
+func addHandlers(s *discordgo.Session) {
 	s.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		if i.Type == discordgo.InteractionApplicationCommand {
 			if h, ok := interactions.CommandHandlers[i.ApplicationCommandData().Name]; ok {
 				h(s, i)
 			}
+		} else if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+			if h, ok := interactions.AutocompleteHandlers[i.ApplicationCommandData().Name]; ok {
+				h(s, i)
+			}
 		} else if i.Type == discordgo.InteractionMessageComponent {
-			if h, ok := interactions.ComponentHandlers[i.MessageComponentData().CustomID]; ok {
+			name, _, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+			if h, ok := interactions.ComponentHandlers[name]; ok {
+				h(s, i)
+			}
+		} else if i.Type == discordgo.InteractionModalSubmit {
+			name, _, _ := strings.Cut(i.ModalSubmitData().CustomID, ":")
+			if h, ok := interactions.ModalHandlers[name]; ok {
 				h(s, i)
 			}
 		}
@@ -36,19 +105,81 @@ func main() {
 			handler(s, m)
 		}
 	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageUpdate) {
+		for _, handler := range interactions.MessageUpdateHandlers {
+			handler(s, m)
+		}
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageDelete) {
+		for _, handler := range interactions.MessageDeleteHandlers {
+			handler(s, m)
+		}
+	})
 	s.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
-		log.Printf("Logged in as: %v#%v", s.State.User.Username, s.State.User.Discriminator)
-		for _, handler := range interactions.ReadyHandlers {
+		log.Printf("Logged in as: %v#%v (shard %d/%d)", s.State.User.Username, s.State.User.Discriminator, s.ShardID, s.ShardCount)
+		for i, handler := range interactions.ReadyHandlers {
+			go func() {
+				err := interactions.Retry(interactions.READY_ATTEMPTS, interactions.READY_RETRY_DELAY, func() error {
+					return handler(s, r)
+				})
+				if err != nil {
+					log.Printf("Ready handler %d failed: %v", i, err)
+				}
+			}()
+		}
+	})
+	s.AddHandler(func(s *discordgo.Session, d *discordgo.Disconnect) {
+		log.Printf("Disconnected from gateway (shard %d/%d)", s.ShardID, s.ShardCount)
+		for _, handler := range interactions.DisconnectHandlers {
+			handler(s, d)
+		}
+	})
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Resumed) {
+		log.Printf("Resumed gateway session (shard %d/%d)", s.ShardID, s.ShardCount)
+		for _, handler := range interactions.ResumedHandlers {
 			handler(s, r)
 		}
 	})
+	s.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
+		for _, handler := range interactions.GuildCreateHandlers {
+			handler(s, g)
+		}
+	})
+	s.AddHandler(func(s *discordgo.Session, g *discordgo.GuildDelete) {
+		for _, handler := range interactions.GuildDeleteHandlers {
+			handler(s, g)
+		}
+	})
+	s.AddHandler(func(s *discordgo.Session, v *discordgo.VoiceStateUpdate) {
+		for _, handler := range interactions.VoiceStateUpdateHandlers {
+			handler(s, v)
+		}
+	})
+}
 
-	err := s.Open()
-	if err != nil {
-		log.Fatalf("Cannot open the session: %v", err)
+func main() {
+	for i, task := range interactions.StartupTasks {
+		err := interactions.Retry(interactions.READY_ATTEMPTS, interactions.READY_RETRY_DELAY, func() error {
+			return task(context.Background())
+		})
+		if err != nil {
+			log.Printf("Startup task %d failed: %v", i, err)
+		}
+	}
+	for i, s := range sessions {
+		addHandlers(s)
+		if i > 0 {
+			time.Sleep(IDENTIFY_DELAY)
+		}
+		err := s.Open()
+		if err != nil {
+			log.Fatalf("Cannot open the session for shard %d: %v", s.ShardID, err)
+		}
+		defer s.Close()
 	}
 
 	/*log.Println("Adding commands...")
+	s := sessions[0]
 	registeredCommands := make([]*discordgo.ApplicationCommand, len(interactions.Commands))
 	for i, v := range interactions.Commands {
 		cmd, err := s.ApplicationCommandCreate(s.State.User.ID, "", v)
@@ -58,12 +189,11 @@ func main() {
 		registeredCommands[i] = cmd
 	}*/
 
-	defer s.Close()
-
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 	log.Println("Press Ctrl+C to exit")
 	<-stop
 
+	interactions.ReleaseLeaderLocks()
 	log.Println("Gracefully shutting down.")
 }